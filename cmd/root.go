@@ -35,6 +35,12 @@ func init() {
 		false,
 		"verbose log output",
 	)
+	rootCmd.PersistentFlags().IntVar(
+		&common.Workers,
+		"workers",
+		1,
+		"default number of files read concurrently, e.g., by 'time-series'",
+	)
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -87,6 +87,11 @@ func run(cmd *cobra.Command, args []string) error {
 
 		var df *dataframe.DataFrame
 		if !onlyGraphs {
+			if !noOutput {
+				if err = rw.CheckOutput(c.Output); err != nil {
+					return fmt.Errorf("output error: %w", err)
+				}
+			}
 			df, err = rw.Read(&c.Input)
 			if err != nil {
 				return fmt.Errorf("error creating data frame: %w", err)
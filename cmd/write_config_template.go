@@ -40,24 +40,72 @@ const (
 - id:                           # optional; pipeline identifier
   input:
     fields: []                  # optional; list of field names
-    type:                       # one of: 'dat', 'csv', 'time-series', 'ram', 'archive', 'multiple'
+    schema:                     # optional; map of field names to types, one of: 'string', 'int', 'float', 'bool', 'time'
+      id: string
+      value:
+        type: float
+        missing: ['N/A', '-']   # optional; missing value tokens, in addition to empty values and 'NaN'
+      stamp:
+        type: time              # or 'datetime'; read as seconds since 'epoch'
+        layout:                 # optional; Go time layout or strftime pattern, e.g., '%Y-%m-%d %H:%M:%S', RFC 3339 by default
+        timezone:               # optional; IANA time zone name, 'UTC' by default
+        epoch:                  # optional; one of 'unix', 'first' or a datetime value, 'unix' by default
+    strict:                     # optional; error on values which cannot be converted, 'false' by default
+    type:                       # one of: 'dat', 'csv', 'fixed-width', 'time-series', 'ram', 'archive', 'multiple', 'foam-field', 'foam-log', 'glob', 'json', 'ndjson', 'probes', 'restart-series', 'sets', 'vtk'
    # some example type specs; there can only be 1 input type per pipeline
     type_spec:
      # 'archive' example
-      file:                     # input archive file name; supports .tar, .tgz, .txz, .tbz, .zip
+      file:                     # input archive file name; supports .tar, .tgz, .txz, .tbz, .zip and nested archives, e.g., 'cases.zip/caseA.tar.xz'
+      lazy:                     # optional; read only the files which are used, 'false' by default
+      memory_budget:            # optional; memory limit, e.g., '512MiB', unlimited by default
       clear_after_read:         # clear memory after reading; 'false' by default
       format_spec:              # config for an input reader, e.g., a 'csv'
         type: csv
         type_spec:
+          file:                 # file path within the archive; glob patterns, e.g., '*/data.csv', are read using 'glob'
           header:
      # 'csv' example
-      file:                     # input file name; usually required
+      file:                     # input file name; usually required; '.gz', '.bz2', '.xz' files are decompressed
       header:                   # optional; 'true' by default
       delimiter:                # optional; ',' by default
       comment:                  # optional; '#' by default
+      decimal_separator:        # optional; '.' by default, e.g., ',' for '1,25'
+      thousands_separator:      # optional; none by default, e.g., '.' for '1.000,5'
      # 'dat' example
       file:                     # input file name; usually required
+      header:                   # optional; read field names from the header, 'false' by default
+      component_suffix:         # optional; one of 'xyz', 'xyz-tensor', 'index', 'xyz' by default
+      component_separator:      # optional; '_' by default
+      decimal_separator:        # optional; '.' by default
+      thousands_separator:      # optional; none by default
+     # 'fixed-width' example
+      file:                     # input file name; usually required
+      widths:                   # list of column widths, e.g., [10, 10, 10]
+      columns:                  # list of column start positions, e.g., [0, 10, 20]; instead of 'widths'
+      header:                   # optional; read field names from the first line, detected by default
+      comment:                  # optional; '#' by default
+     # 'foam-field' example
+      file:                     # field file name, e.g., '0/U'
+      field:                    # optional; field name, header 'object' entry by default
+      patch:                    # optional; boundary patch name, 'internalField' by default
+     # 'foam-log' example
+      file:                     # solver log file name
+      time_name:                # 'time' by default
+      component_suffix:         # optional; one of 'xyz', 'xyz-tensor', 'index', 'xyz' by default
+     # 'glob' example
+      pattern:                  # glob pattern, supports '**', e.g., 'runs/*/force.dat'
+      tag_name:                 # optional; path field name, 'source' by default
+      tag_regexp:               # optional; named capture groups become fields
+      format_spec:              # config for an input type reader, e.g., a 'dat'
+        type: dat
+     # 'json' or 'ndjson' example
+      file:                     # input file name
+      keep_strings:             # optional; keep JSON string fields as strings, 'false' by default
      # 'multiple' example
+      join:                     # optional; one of 'inner', 'left', 'outer', by row position by default
+      keys:                     # optional; key field names, needed if 'join' is set
+      tolerance:                # optional; numeric key tolerance, '0' by default
+      suffixes:                 # optional; field name suffixes, one per input, '_<i>' by default
       format_specs:              # configs for multiple input readers, e.g., 'csv' and 'dat'
         - type: csv
           type_spec:
@@ -66,17 +114,50 @@ const (
         - type: dat
           type_spec:
             file:
+     # 'probes' example
+      file:                     # probes file name
+      field:                    # optional; probed field name, file name by default
+      time_name:                # 'time' by default
+      long:                     # optional; output one row per time and probe, 'false' by default
+      locations:                # optional; output probe location fields in wide format, 'false' by default
      # 'ram' example
       name:                     # name of the data which will be accessed
       clear_after_read:         # clear memory after reading; 'false' by default
+     # 'restart-series' example
+      directory:                # directory containing the start-time directories
+      file:                     # data file name, e.g., 'p'
+      time_name:                # 'time' by default
+      skip_non_numeric:         # optional; skip non-numeric directories, 'false' by default
+      format_spec:              # config for an input type reader, e.g., 'probes'
+        type: probes
+     # 'sets' example
+      file:                     # set data file name, e.g., 'lineA_p_U.xy'
+      set:                      # optional; set name
+      axis:                     # optional; one of 'distance', 'x', 'y', 'z', 'xyz', 'distance' by default
+      fields:                   # optional; list of field names, derived from the file name by default
+      components:               # optional; map of field names to component counts, e.g., 'U: 3'
+      directory:                # optional; series root directory
+      times:                    # optional; list of times to read, all by default
      # 'time-series' example
       directory:                # series root directory
       file:                     # series data file name
       time_name:                # 'time' by default
+      times:                    # optional; list of times to read, all by default
+      skip_non_numeric:         # optional; skip non-numeric directories, 'false' by default
+      start_time:               # optional; earliest time to read
+      end_time:                 # optional; latest time to read
+      stride:                   # optional; read every N-th time, '1' by default
+      latest:                   # optional; read only the latest N times
+      earliest:                 # optional; read only the earliest N times
+      workers:                  # optional; number of files read concurrently, '--workers' by default
       format_spec:              # config for an input type reader, e.g., a 'csv'
         type: csv
         type_spec:
           header:
+     # 'vtk' example
+      file:                     # legacy or XML (.vtp, .vtu) VTK file name
+      data:                     # optional; one of 'point', 'cell', 'point' by default
+      component_suffix:         # optional; one of 'xyz', 'xyz-tensor', 'index', 'index' by default
   process:
    # some example processor specs, executed in order listed
     - type: assert-equal
@@ -124,15 +205,60 @@ const (
         - field:
           descending:
   output:
-   # some example specs
+   # some example specs; each output can define 'max_rows', 'decimation' and 'split_by'
+    - type: csv
+      split_by:                 # optional; list of fields, one output per distinct combination of values
+      max_rows:                 # optional; decimate data with more rows, unlimited by default
+      decimation:
+        method:                 # optional; one of 'stride', 'minmax', 'lttb', 'stride' by default
+        x_field:                # optional; abscissa field name for 'lttb', row index by default
+        fields:                 # optional; fields whose shape is preserved, all numeric fields by default
+      type_spec:
+        file:                   # '{field}' placeholders are replaced by 'split_by' field values
     - type: ram
       type_spec:
         name:                   # key name under which data will be stored
         clear_after_read:       # clear memory after reading; 'false' by default
     - type: csv
+      type_spec:
+        file:                   # output file name; compressed if it ends with '.gz' or '.xz'
+        header:                 # optional; 'true' by default
+        delimiter:              # optional; ',' by default
+        float_format:           # optional; fmt float format, e.g., '%g', '%.6e', '%f' by default
+        precision:              # optional; map of field names to precisions
+        nan:                    # optional; NaN spelling, 'NaN' by default
+        inf:                    # optional; infinity spelling, '+Inf' by default
+        decimal_separator:      # optional; '.' by default
+        thousands_separator:    # optional; none by default
+        enforce_extension:      # optional; force correct file extension, by default 'false'
+        datetime:               # optional; map of field names to datetime formats
+          stamp:
+            layout:             # optional; Go time layout or strftime pattern, RFC 3339 by default
+            timezone:           # optional; IANA time zone name, 'UTC' by default
+            epoch:              # optional; 'unix' or a datetime value, 'unix' by default
+    - type: dat
       type_spec:
         file:                   # output file name
+        header:                 # optional; write a '#'-prefixed header line, 'false' by default
         enforce_extension:      # optional; force correct file extension, by default 'false'
+    - type: fixed-width
+      type_spec:
+        file:                   # output file name
+        widths:                 # optional; list of column widths, widest value by default
+        columns:                # optional; list of column start positions, instead of 'widths'
+        header:                 # optional; 'true' by default
+    - type: json                # or 'ndjson'
+      type_spec:
+        file:                   # output file name
+        orient:                 # optional; one of 'records', 'columns', 'records' by default
+    - type: latex-table         # or 'markdown-table'
+      type_spec:
+        file:                   # output file name
+        labels:                 # optional; map of field names to column header labels
+          x:
+        align:                  # optional; column alignment, e.g., 'lrS', by field type by default
+        siunitx:                # optional; 'latex-table' only, use 'S' numeric columns, 'false' by default
+        float_format:           # optional; fmt float format, '%g' by default
   graph:
     type:                       # only 'tex' currently
     graphs:
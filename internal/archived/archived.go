@@ -10,6 +10,7 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -79,12 +80,23 @@ type fileEntry struct {
 // NewFS reads an archive into memory and constructs an in memory file system.
 // The returned fs.FS points to the root of the file system.
 func NewFS(name string) (fs.FS, error) {
-	var fe fileEntry
-	// FIXME: this is actually disgusting
-	info, err := os.Stat(name)
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
 	if err != nil {
 		return nil, err
 	}
+	return NewFSFrom(info, f)
+}
+
+// NewFSFrom reads an archive, described by info, from r into memory
+// and constructs an in memory file system.
+// The returned fs.FS points to the root of the file system.
+func NewFSFrom(info fs.FileInfo, r io.ReaderAt) (fs.FS, error) {
+	var fe fileEntry
 	fe.Info = fileInfo{
 		name:    info.Name(),
 		size:    info.Size(),
@@ -93,15 +105,11 @@ func NewFS(name string) (fs.FS, error) {
 		isDir:   true,
 	}
 
-	format := MatchFormat(name)
+	format := MatchFormat(info.Name())
 	switch format {
 	case A_TAR, A_TXZ, A_TGZ, A_TBZ:
-		f, err := os.Open(name)
-		if err != nil {
-			return nil, err
-		}
-		defer f.Close()
-		var reader io.Reader = f
+		var reader io.Reader = io.NewSectionReader(r, 0, info.Size())
+		var err error
 		switch format {
 		case A_TXZ:
 			reader, err = xz.NewReader(reader)
@@ -154,11 +162,10 @@ func NewFS(name string) (fs.FS, error) {
 			*currentDir = append(*currentDir, entry)
 		}
 	case A_ZIP:
-		z, err := zip.OpenReader(name)
+		z, err := zip.NewReader(r, info.Size())
 		if err != nil {
 			return nil, err
 		}
-		defer z.Close()
 		// walk the archive and build the filesystem
 		currentDir := &fe.Files
 		currentDirPath := "."
@@ -190,12 +197,53 @@ func NewFS(name string) (fs.FS, error) {
 			}
 		}
 	default:
-		return nil, &fs.PathError{Op: "stat", Path: name, Err: os.ErrInvalid}
+		return nil, &fs.PathError{Op: "stat", Path: info.Name(), Err: os.ErrInvalid}
 	}
 	fe.Files.sort()
 	return &fe, nil
 }
 
+// Size returns the total size of the file bodies of an archive file system
+// held in memory.
+func Size(fsys fs.FS) uint64 {
+	switch f := fsys.(type) {
+	case *fileEntry:
+		return f.size()
+	case interface{ Size() uint64 }:
+		return f.Size()
+	}
+	return 0
+}
+
+// Prefetch announces that the named files of an archive file system are
+// about to be read, which lets lazily read archives avoid decompressing
+// compressed archive streams repeatedly. File systems which do not
+// support prefetching are unaffected.
+func Prefetch(fsys fs.FS, names []string) {
+	if f, ok := fsys.(interface{ Prefetch([]string) }); ok {
+		f.Prefetch(names)
+	}
+}
+
+// joinAll joins dir with each of names.
+func joinAll(dir string, names []string) []string {
+	joined := make([]string, len(names))
+	for i, name := range names {
+		joined[i] = path.Join(dir, name)
+	}
+	return joined
+}
+
+// size returns the total size of the bodies of fe and all fileEntries
+// contained within it.
+func (fe *fileEntry) size() uint64 {
+	s := uint64(len(fe.Body))
+	for _, f := range fe.Files {
+		s += f.size()
+	}
+	return s
+}
+
 func (f *fileEntry) Stat() (fs.FileInfo, error) { return f.Info, nil }
 func (f *fileEntry) Close() error               { return nil }
 func (f *fileEntry) ResetReader()               { f.r.Reset(f.Body) }
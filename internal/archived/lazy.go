@@ -0,0 +1,654 @@
+package archived
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"compress/bzip2"
+	"compress/gzip"
+	"container/list"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ulikunitz/xz"
+)
+
+// lazyEntry is an index entry of a file within a lazily read archive.
+// If the file is a directory, the lazyEntry also holds a list of all
+// lazyEntries contained within the directory.
+type lazyEntry struct {
+	Info  fs.FileInfo
+	Files []*lazyEntry
+
+	// zf is the zip archive file of the entry.
+	zf *zip.File
+	// index is the position of the entry in a tar archive.
+	index int
+	// offset is the offset of the entry body in an uncompressed tar archive,
+	// or -1 if the body cannot be read directly.
+	offset int64
+}
+
+// sort recursively sorts the entries contained within e.
+func (e *lazyEntry) sort() {
+	slices.SortFunc(e.Files, func(a, b *lazyEntry) int {
+		return cmp.Compare(a.Info.Name(), b.Info.Name())
+	})
+	for _, f := range e.Files {
+		f.sort()
+	}
+}
+
+// bodyCache is a least-recently-used cache of file bodies,
+// whose total size is limited by a memory budget. A bodyCache can be
+// shared by several LazyFSs, e.g., by archives nested within an archive,
+// and can hold pinned memory, e.g., nested archive bodies, which counts
+// against the budget but is never evicted.
+type bodyCache struct {
+	mu     sync.Mutex
+	budget uint64
+	used   uint64
+	pinned uint64
+	owned  map[*LazyFS]uint64
+	ll     *list.List
+	m      map[*lazyEntry]*list.Element
+}
+
+type cacheItem struct {
+	owner *LazyFS
+	e     *lazyEntry
+	body  []byte
+}
+
+func newBodyCache(budget uint64) *bodyCache {
+	return &bodyCache{
+		budget: budget,
+		owned:  make(map[*LazyFS]uint64),
+		ll:     list.New(),
+		m:      make(map[*lazyEntry]*list.Element),
+	}
+}
+
+// get returns the cached body of e, if it exists.
+func (c *bodyCache) get(e *lazyEntry) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, found := c.m[e]
+	if !found {
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*cacheItem).body, true
+}
+
+// has reports whether the body of e is cached.
+func (c *bodyCache) has(e *lazyEntry) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, found := c.m[e]
+	return found
+}
+
+// put caches the body of e, owned by owner, evicting the least recently
+// used bodies if the budget is exceeded. Bodies which do not fit within
+// the budget, e.g., due to pinned memory, are not cached.
+func (c *bodyCache) put(owner *LazyFS, e *lazyEntry, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	size := uint64(len(body))
+	if _, found := c.m[e]; found || (c.budget > 0 && size > c.budget) {
+		return
+	}
+	for c.budget > 0 && c.used+c.pinned+size > c.budget && c.ll.Len() > 0 {
+		c.remove(c.ll.Back())
+	}
+	if c.budget > 0 && c.used+c.pinned+size > c.budget {
+		return
+	}
+	c.m[e] = c.ll.PushFront(&cacheItem{owner: owner, e: e, body: body})
+	c.used += size
+	c.owned[owner] += size
+}
+
+// remove removes a cached body.
+func (c *bodyCache) remove(el *list.Element) {
+	item := c.ll.Remove(el).(*cacheItem)
+	delete(c.m, item.e)
+	size := uint64(len(item.body))
+	c.used -= size
+	if c.owned[item.owner] -= size; c.owned[item.owner] == 0 {
+		delete(c.owned, item.owner)
+	}
+}
+
+// forget removes the cached body of e, if it exists.
+func (c *bodyCache) forget(e *lazyEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, found := c.m[e]; found {
+		c.remove(el)
+	}
+}
+
+// drop removes all cached bodies owned by owner.
+func (c *bodyCache) drop(owner *LazyFS) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for el := c.ll.Front(); el != nil; {
+		next := el.Next()
+		if el.Value.(*cacheItem).owner == owner {
+			c.remove(el)
+		}
+		el = next
+	}
+}
+
+// pin adds n bytes of pinned memory, evicting the least recently used
+// bodies if the budget is exceeded.
+func (c *bodyCache) pin(n uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pinned += n
+	for c.budget > 0 && c.used+c.pinned > c.budget && c.ll.Len() > 0 {
+		c.remove(c.ll.Back())
+	}
+}
+
+// unpin removes n bytes of pinned memory.
+func (c *bodyCache) unpin(n uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pinned -= min(n, c.pinned)
+}
+
+// size returns the total size of the cached bodies owned by owner.
+func (c *bodyCache) size(owner *LazyFS) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.owned[owner]
+}
+
+// total returns the total size of the cached bodies and pinned memory.
+func (c *bodyCache) total() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.used + c.pinned
+}
+
+// tarCursor is a position within a (compressed) tar archive stream.
+type tarCursor struct {
+	tr   *tar.Reader
+	next int // index of the next entry in the stream
+}
+
+// LazyFS is a read-only file system of an archive, which reads
+// file bodies from the archive only when they are read, and keeps them
+// in memory within a memory budget.
+//
+// Zip archive files are accessed directly through the central directory,
+// while tar archives are indexed in a single pass. Bodies of uncompressed tar
+// archive files are read directly from their offsets, whereas compressed
+// tar archives are decompressed up to the file being read, and decompression
+// is restarted only if an earlier file is read. To avoid restarts, files
+// which are about to be read should be announced by Prefetch, in which case
+// their bodies are kept, within the memory budget, when the archive stream
+// is advanced past them.
+type LazyFS struct {
+	format ArchiveFormat
+	root   *lazyEntry
+	cache  *bodyCache
+
+	// r is the archive reader of size bytes.
+	r    io.ReaderAt
+	size int64
+	// c closes the archive file, if it was opened by the LazyFS.
+	c io.Closer
+
+	// entries are the tar archive entries, in archive order.
+	entries []*lazyEntry
+
+	mu       sync.Mutex // guards cur, restarts and closed
+	cur      *tarCursor
+	restarts int
+	closed   bool
+
+	wmu    sync.Mutex // guards wanted
+	wanted map[*lazyEntry]bool
+}
+
+// NewLazyFS indexes an archive and constructs a lazily read file system,
+// which keeps at most budget bytes of file bodies in memory,
+// or an unlimited amount if budget is 0.
+func NewLazyFS(name string, budget uint64) (*LazyFS, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	lfs, err := NewLazyFSFrom(info, f, budget)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	lfs.c = f
+	return lfs, nil
+}
+
+// NewLazyFSFrom indexes an archive, described by info, which is read
+// from r, and constructs a lazily read file system, which keeps at most
+// budget bytes of file bodies in memory, or an unlimited amount
+// if budget is 0.
+func NewLazyFSFrom(info fs.FileInfo, r io.ReaderAt, budget uint64) (*LazyFS, error) {
+	return newLazyFSFrom(info, r, newBodyCache(budget))
+}
+
+// newLazyFSFrom indexes an archive, described by info, which is read
+// from r, and constructs a lazily read file system, which keeps file
+// bodies in cache.
+func newLazyFSFrom(info fs.FileInfo, r io.ReaderAt, cache *bodyCache) (*LazyFS, error) {
+	lfs := &LazyFS{
+		format: MatchFormat(info.Name()),
+		root: &lazyEntry{Info: fileInfo{
+			name:    info.Name(),
+			mode:    fs.ModeDir,
+			modTime: info.ModTime(),
+			isDir:   true,
+		}},
+		cache:  cache,
+		r:      r,
+		size:   info.Size(),
+		wanted: make(map[*lazyEntry]bool),
+	}
+	var err error
+	switch lfs.format {
+	case A_TAR, A_TXZ, A_TGZ, A_TBZ:
+		err = lfs.indexTar()
+	case A_ZIP:
+		err = lfs.indexZip()
+	default:
+		err = &fs.PathError{Op: "stat", Path: info.Name(), Err: os.ErrInvalid}
+	}
+	if err != nil {
+		return nil, err
+	}
+	lfs.root.sort()
+	return lfs, nil
+}
+
+// add adds an entry to the index at path p, creating any missing parent
+// directories. An existing entry at p is replaced. It returns the added
+// entry, which is the replaced entry if it existed.
+func (lfs *LazyFS) add(p string, e *lazyEntry) *lazyEntry {
+	p = strings.TrimPrefix(path.Clean("/"+p), "/")
+	if p == "" {
+		return e
+	}
+	dir := lfs.root
+	parts := strings.Split(p, "/")
+	for i, name := range parts {
+		var child *lazyEntry
+		for _, f := range dir.Files {
+			if f.Info.Name() == name {
+				child = f
+				break
+			}
+		}
+		if i == len(parts)-1 {
+			if child != nil && child.Info.IsDir() && e.Info.IsDir() {
+				child.Info = e.Info // keep the contents
+				return child
+			}
+			if child != nil {
+				*child = *e
+				return child
+			}
+			dir.Files = append(dir.Files, e)
+			return e
+		}
+		if child == nil {
+			child = &lazyEntry{Info: fileInfo{
+				name:  name,
+				mode:  fs.ModeDir,
+				isDir: true,
+			}}
+			dir.Files = append(dir.Files, child)
+		}
+		dir = child
+	}
+	return e
+}
+
+func (lfs *LazyFS) indexZip() error {
+	z, err := zip.NewReader(lfs.r, lfs.size)
+	if err != nil {
+		return err
+	}
+	for _, file := range z.File {
+		_ = lfs.add(file.Name, &lazyEntry{Info: file.FileInfo(), zf: file})
+	}
+	return nil
+}
+
+// openTar opens a tar archive stream from the start, and returns
+// the underlying (compressed) archive stream.
+func (lfs *LazyFS) openTar() (*tarCursor, *io.SectionReader, error) {
+	sr := io.NewSectionReader(lfs.r, 0, lfs.size)
+	var r io.Reader = sr
+	var err error
+	switch lfs.format {
+	case A_TXZ:
+		r, err = xz.NewReader(r)
+	case A_TGZ:
+		r, err = gzip.NewReader(r)
+	case A_TBZ:
+		r = bzip2.NewReader(r)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return &tarCursor{tr: tar.NewReader(r)}, sr, nil
+}
+
+func (lfs *LazyFS) indexTar() error {
+	c, sr, err := lfs.openTar()
+	if err != nil {
+		return err
+	}
+	for i := 0; ; i++ {
+		hdr, err := c.tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		e := &lazyEntry{Info: hdr.FileInfo(), index: i, offset: -1}
+		// the tar reader reads only whole headers, so the stream offset
+		// is the offset of the body
+		if lfs.format == A_TAR && hdr.Typeflag != tar.TypeGNUSparse {
+			if e.offset, err = sr.Seek(0, io.SeekCurrent); err != nil {
+				return err
+			}
+		}
+		lfs.entries = append(lfs.entries, lfs.add(hdr.Name, e))
+	}
+	return nil
+}
+
+// body returns the body of the file entry e.
+func (lfs *LazyFS) body(e *lazyEntry) ([]byte, error) {
+	if b, found := lfs.cache.get(e); found {
+		lfs.unwant(e)
+		return b, nil
+	}
+	var b []byte
+	var err error
+	switch {
+	case e.zf != nil:
+		var rc io.ReadCloser
+		if rc, err = e.zf.Open(); err != nil {
+			return nil, err
+		}
+		b, err = io.ReadAll(rc)
+		rc.Close()
+	case e.offset >= 0:
+		b = make([]byte, e.Info.Size())
+		_, err = lfs.r.ReadAt(b, e.offset)
+	default:
+		b, err = lfs.streamBody(e)
+	}
+	if err != nil {
+		return nil, err
+	}
+	lfs.put(e, b)
+	return b, nil
+}
+
+// put caches the body of e, unless the LazyFS is closed.
+func (lfs *LazyFS) put(e *lazyEntry, b []byte) {
+	lfs.mu.Lock()
+	closed := lfs.closed
+	lfs.mu.Unlock()
+	if !closed {
+		lfs.cache.put(lfs, e, b)
+	}
+}
+
+// streamBody reads the body of the tar archive entry e by advancing
+// the archive stream to it. The bodies of wanted entries which are passed
+// over are cached.
+func (lfs *LazyFS) streamBody(e *lazyEntry) ([]byte, error) {
+	lfs.mu.Lock()
+	defer lfs.mu.Unlock()
+	// the body may have been cached while waiting
+	if b, found := lfs.cache.get(e); found {
+		lfs.unwant(e)
+		return b, nil
+	}
+	if lfs.cur == nil || lfs.cur.next > e.index {
+		c, _, err := lfs.openTar()
+		if err != nil {
+			return nil, err
+		}
+		if lfs.cur != nil {
+			lfs.restarts++
+		}
+		lfs.cur = c
+	}
+	for ; lfs.cur.next <= e.index; lfs.cur.next++ {
+		if _, err := lfs.cur.tr.Next(); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		if lfs.cur.next == e.index {
+			break
+		}
+		// keep the bodies of wanted entries, skip the rest
+		passed := lfs.entries[lfs.cur.next]
+		if passed.index != lfs.cur.next || !lfs.isWanted(passed) || lfs.cache.has(passed) {
+			continue
+		}
+		b, err := io.ReadAll(lfs.cur.tr)
+		if err != nil {
+			return nil, err
+		}
+		if !lfs.closed {
+			lfs.cache.put(lfs, passed, b)
+		}
+	}
+	lfs.cur.next = e.index + 1
+	lfs.unwant(e)
+	return io.ReadAll(lfs.cur.tr)
+}
+
+// isWanted reports whether e was prefetched, but not yet read.
+func (lfs *LazyFS) isWanted(e *lazyEntry) bool {
+	lfs.wmu.Lock()
+	defer lfs.wmu.Unlock()
+	return lfs.wanted[e]
+}
+
+// unwant marks e as read.
+func (lfs *LazyFS) unwant(e *lazyEntry) {
+	lfs.wmu.Lock()
+	defer lfs.wmu.Unlock()
+	delete(lfs.wanted, e)
+}
+
+// Prefetch announces that the named files are about to be read, possibly
+// concurrently and in any order. Only compressed tar archives are
+// affected, whose streams are decompressed once for all announced files
+// read in archive order, instead of once per file read out of order,
+// provided their bodies fit within the memory budget.
+// Names which do not exist are ignored.
+func (lfs *LazyFS) Prefetch(names []string) {
+	lfs.wmu.Lock()
+	defer lfs.wmu.Unlock()
+	for _, name := range names {
+		if !fs.ValidPath(name) {
+			continue
+		}
+		e, err := lfs.find(name)
+		if err != nil || e.Info.IsDir() || e.zf != nil || e.offset >= 0 {
+			continue
+		}
+		lfs.wanted[e] = true
+	}
+}
+
+// find searches for an entry from a path.
+func (lfs *LazyFS) find(name string) (*lazyEntry, error) {
+	e := lfs.root
+	if name == "." {
+		return e, nil
+	}
+	for _, part := range strings.Split(name, "/") {
+		var child *lazyEntry
+		for _, f := range e.Files {
+			if f.Info.Name() == part {
+				child = f
+				break
+			}
+		}
+		if child == nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+		}
+		e = child
+	}
+	return e, nil
+}
+
+// Open opens the named file. Directories are returned as fs.FS rooted
+// at the directory. The file body is read from the archive once the file
+// is read.
+func (lfs *LazyFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	e, err := lfs.find(name)
+	if err != nil {
+		return nil, err
+	}
+	if e.Info.IsDir() {
+		return &lazyDir{fsys: lfs, path: name, e: e}, nil
+	}
+	return &lazyFile{fsys: lfs, e: e}, nil
+}
+
+// ReadDir reads the named directory and returns a list of directory
+// entries sorted by file name.
+func (lfs *LazyFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	e, err := lfs.find(name)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]fs.DirEntry, len(e.Files))
+	for i, f := range e.Files {
+		entries[i] = fs.FileInfoToDirEntry(f.Info)
+	}
+	return entries, nil
+}
+
+// forget removes the body of the named file from memory, e.g., if it is
+// held elsewhere.
+func (lfs *LazyFS) forget(name string) {
+	if e, err := lfs.find(name); err == nil {
+		lfs.cache.forget(e)
+	}
+}
+
+// Size returns the total size of the file bodies held in memory.
+func (lfs *LazyFS) Size() uint64 {
+	return lfs.cache.size(lfs)
+}
+
+// Close releases the file bodies held in memory and closes the archive,
+// if it was opened by the LazyFS.
+func (lfs *LazyFS) Close() error {
+	lfs.mu.Lock()
+	defer lfs.mu.Unlock()
+	lfs.cur = nil
+	lfs.closed = true
+	lfs.cache.drop(lfs)
+	lfs.wmu.Lock()
+	lfs.wanted = make(map[*lazyEntry]bool)
+	lfs.wmu.Unlock()
+	if lfs.c != nil {
+		return lfs.c.Close()
+	}
+	return nil
+}
+
+// lazyFile is an open regular file of a LazyFS.
+type lazyFile struct {
+	fsys *LazyFS
+	e    *lazyEntry
+	r    *bytes.Reader
+}
+
+func (f *lazyFile) Stat() (fs.FileInfo, error) { return f.e.Info, nil }
+func (f *lazyFile) Close() error               { return nil }
+
+// Read reads the file body into p, reading it from the archive
+// on the first call.
+func (f *lazyFile) Read(p []byte) (int, error) {
+	if f.r == nil {
+		b, err := f.fsys.body(f.e)
+		if err != nil {
+			return 0, err
+		}
+		f.r = bytes.NewReader(b)
+	}
+	return f.r.Read(p)
+}
+
+// lazyDir is an open directory of a LazyFS, which is also a file system
+// rooted at the directory.
+type lazyDir struct {
+	fsys *LazyFS
+	path string
+	e    *lazyEntry
+}
+
+func (d *lazyDir) Stat() (fs.FileInfo, error) { return d.e.Info, nil }
+func (d *lazyDir) Close() error               { return nil }
+
+func (d *lazyDir) Read(p []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.path, Err: errors.New("is a directory")}
+}
+
+func (d *lazyDir) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	return d.fsys.Open(path.Join(d.path, name))
+}
+
+// Prefetch announces that the named files, relative to the directory,
+// are about to be read, as for LazyFS.
+func (d *lazyDir) Prefetch(names []string) {
+	d.fsys.Prefetch(joinAll(d.path, names))
+}
+
+func (d *lazyDir) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	return d.fsys.ReadDir(path.Join(d.path, name))
+}
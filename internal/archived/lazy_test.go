@@ -0,0 +1,135 @@
+package archived
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tgz returns a gzip compressed tar archive of files, written in order,
+// preceded by their parent directories.
+func tgz(t *testing.T, files [][2]string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	dirs := make(map[string]bool)
+	for _, f := range files {
+		if dir := path.Dir(f[0]); dir != "." && !dirs[dir] {
+			hdr := &tar.Header{Name: dir + "/", Mode: 0755, Typeflag: tar.TypeDir}
+			if err := tw.WriteHeader(hdr); err != nil {
+				t.Fatal(err)
+			}
+			dirs[dir] = true
+		}
+		hdr := &tar.Header{Name: f[0], Mode: 0644, Size: int64(len(f[1]))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(f[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// writeTemp writes b to a file named name in a temporary directory,
+// and returns the file path.
+func writeTemp(t *testing.T, name string, b []byte) string {
+	p := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(p, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+var seriesFiles = [][2]string{
+	{"0/data.csv", "x\n0\n"},
+	{"1/data.csv", "x\n1\n"},
+	{"2/data.csv", "x\n2\n"},
+	{"3/data.csv", "x\n3\n"},
+}
+
+type lazyStreamTest struct {
+	Name     string
+	Prefetch bool
+	Budget   uint64
+	Restarts int
+}
+
+var lazyStreamTests = []lazyStreamTest{
+	{
+		Name:     "no-prefetch",
+		Prefetch: false,
+		Restarts: 3,
+	},
+	{
+		Name:     "prefetch",
+		Prefetch: true,
+		Restarts: 0,
+	},
+	{
+		Name:     "prefetch-budget",
+		Prefetch: true,
+		Budget:   8, // only two bodies are kept
+		Restarts: 1,
+	},
+}
+
+func TestLazyFSStream(t *testing.T) {
+	name := writeTemp(t, "series.tgz", tgz(t, seriesFiles))
+	for _, tt := range lazyStreamTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			lfs, err := NewLazyFS(name, tt.Budget)
+			assert.Nil(err, "unexpected NewLazyFS() error")
+			defer lfs.Close()
+			if tt.Prefetch {
+				var names []string
+				for _, f := range seriesFiles {
+					names = append(names, f[0])
+				}
+				Prefetch(lfs, names)
+			}
+			// read in reverse archive order
+			for i := len(seriesFiles) - 1; i >= 0; i-- {
+				b, err := fs.ReadFile(lfs, seriesFiles[i][0])
+				assert.Nil(err)
+				assert.Equal(seriesFiles[i][1], string(b))
+			}
+			assert.Equal(tt.Restarts, lfs.restarts)
+			if tt.Budget > 0 {
+				assert.LessOrEqual(lfs.Size(), tt.Budget)
+			}
+		})
+	}
+}
+
+func TestLazyFSClose(t *testing.T) {
+	assert := assert.New(t)
+
+	name := writeTemp(t, "series.tgz", tgz(t, seriesFiles))
+	lfs, err := NewLazyFS(name, 0)
+	assert.Nil(err, "unexpected NewLazyFS() error")
+	f, err := lfs.Open(seriesFiles[0][0])
+	assert.Nil(err)
+	_, err = io.ReadAll(f)
+	assert.Nil(err)
+	assert.NotZero(lfs.Size())
+	assert.Nil(lfs.Close())
+	assert.Zero(lfs.Size())
+}
@@ -0,0 +1,316 @@
+package archived
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// NestedFS is a read-only file system which opens archives nested within
+// it transparently, i.e., archive files are treated as directories holding
+// the archive contents, hence paths may traverse archives,
+// e.g., 'caseA.tar.xz/postProcessing/forces/0/force.dat'.
+//
+// Nested archives are read into memory when they are first opened,
+// and are either read entirely, or lazily, as for LazyFS. Nested archives
+// count against the memory budget, and the least recently used ones are
+// evicted from memory if it is exceeded.
+type NestedFS struct {
+	fsys   fs.FS
+	lazy   bool
+	budget uint64
+	// cache is shared by all lazily read archives within the NestedFS,
+	// and holds the nested archive bodies as pinned memory.
+	cache *bodyCache
+
+	mu     sync.Mutex // guards nested and order
+	nested map[string]*nestedArchive
+	order  []string // nested archive paths, least recently used first
+}
+
+// nestedArchive is an archive opened from within a NestedFS.
+type nestedArchive struct {
+	fsys *NestedFS
+	// size is the size of the archive body, which is kept in memory
+	// if the archive is read lazily.
+	size uint64
+}
+
+// NewNestedFS constructs a NestedFS from fsys. Nested archives are read
+// lazily if lazy is set. At most budget bytes are kept in memory, shared
+// with fsys if it is a LazyFS, or an unlimited amount if budget is 0.
+func NewNestedFS(fsys fs.FS, lazy bool, budget uint64) *NestedFS {
+	cache := newBodyCache(budget)
+	if lfs, ok := fsys.(*LazyFS); ok {
+		cache = lfs.cache
+	}
+	return newNestedFS(fsys, lazy, budget, cache)
+}
+
+func newNestedFS(fsys fs.FS, lazy bool, budget uint64, cache *bodyCache) *NestedFS {
+	return &NestedFS{
+		fsys:   fsys,
+		lazy:   lazy,
+		budget: budget,
+		cache:  cache,
+		nested: make(map[string]*nestedArchive),
+	}
+}
+
+// isArchive reports whether the file name has an archive extension.
+func isArchive(name string) bool {
+	return MatchFormat(name) != A_UNKNOWN
+}
+
+// resolve returns the innermost NestedFS containing the file name,
+// and the path of the file within it.
+func (n *NestedFS) resolve(name string) (*NestedFS, string, error) {
+	if name == "." {
+		return n, name, nil
+	}
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		if !isArchive(part) {
+			continue
+		}
+		p := strings.Join(parts[:i+1], "/")
+		info, err := fs.Stat(n.fsys, p)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		sub, err := n.open(p, info)
+		if err != nil {
+			return nil, "", err
+		}
+		rest := "."
+		if i+1 < len(parts) {
+			rest = strings.Join(parts[i+1:], "/")
+		}
+		return sub.resolve(rest)
+	}
+	return n, name, nil
+}
+
+// open opens the archive file p, described by info.
+func (n *NestedFS) open(p string, info fs.FileInfo) (*NestedFS, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if a, found := n.nested[p]; found {
+		n.touch(p)
+		return a.fsys, nil
+	}
+	f, err := n.fsys.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+	// the archive body is accounted for by the nested archive
+	if lfs, ok := n.fsys.(*LazyFS); ok {
+		lfs.forget(p)
+	}
+	info = fileInfo{
+		name:    info.Name(),
+		size:    int64(len(body)),
+		mode:    info.Mode(),
+		modTime: info.ModTime(),
+	}
+	var fsys fs.FS
+	a := &nestedArchive{}
+	if n.lazy {
+		fsys, err = newLazyFSFrom(info, bytes.NewReader(body), n.cache)
+		a.size = uint64(len(body))
+	} else {
+		fsys, err = NewFSFrom(info, bytes.NewReader(body))
+	}
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: p, Err: err}
+	}
+	n.cache.pin(a.size)
+	a.fsys = newNestedFS(fsys, n.lazy, n.budget, n.cache)
+	n.nested[p] = a
+	n.touch(p)
+	n.evict()
+	return a.fsys, nil
+}
+
+// touch marks the nested archive p as the most recently used.
+func (n *NestedFS) touch(p string) {
+	if i := slices.Index(n.order, p); i >= 0 {
+		n.order = slices.Delete(n.order, i, i+1)
+	}
+	n.order = append(n.order, p)
+}
+
+// evict removes the least recently used nested archives from memory,
+// except the most recently used one, until the memory budget is met.
+func (n *NestedFS) evict() {
+	if n.budget == 0 {
+		return
+	}
+	for n.size() > n.budget && len(n.order) > 1 {
+		n.remove(n.order[0])
+	}
+}
+
+// remove removes the nested archive p from memory.
+func (n *NestedFS) remove(p string) error {
+	a := n.nested[p]
+	delete(n.nested, p)
+	if i := slices.Index(n.order, p); i >= 0 {
+		n.order = slices.Delete(n.order, i, i+1)
+	}
+	err := a.fsys.Close()
+	n.cache.unpin(a.size)
+	return err
+}
+
+// Open opens the named file. Directories, including archive files,
+// are returned as fs.FS rooted at the directory.
+func (n *NestedFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	sub, p, err := n.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := sub.fsys.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if !info.IsDir() {
+		return f, nil
+	}
+	f.Close()
+	return &nestedDir{fsys: n, path: name, info: info}, nil
+}
+
+// ReadDir reads the named directory and returns a list of directory
+// entries sorted by file name. Archive files are listed as directories.
+func (n *NestedFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	sub, p, err := n.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := fs.ReadDir(sub.fsys, p)
+	if err != nil {
+		return nil, err
+	}
+	for i, e := range entries {
+		if e.IsDir() || !isArchive(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = fs.FileInfoToDirEntry(fileInfo{
+			name:    info.Name(),
+			mode:    fs.ModeDir,
+			modTime: info.ModTime(),
+			isDir:   true,
+		})
+	}
+	return entries, nil
+}
+
+// Prefetch announces that the named files are about to be read,
+// as for LazyFS. Nested archives along the paths are opened.
+func (n *NestedFS) Prefetch(names []string) {
+	subs := make(map[*NestedFS][]string)
+	for _, name := range names {
+		if !fs.ValidPath(name) {
+			continue
+		}
+		sub, p, err := n.resolve(name)
+		if err != nil {
+			continue
+		}
+		subs[sub] = append(subs[sub], p)
+	}
+	for sub, ps := range subs {
+		Prefetch(sub.fsys, ps)
+	}
+}
+
+// Size returns the total size of the file system held in memory,
+// including nested archives.
+func (n *NestedFS) Size() uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.size()
+}
+
+func (n *NestedFS) size() uint64 {
+	s := Size(n.fsys)
+	for _, a := range n.nested {
+		s += a.size + a.fsys.Size()
+	}
+	return s
+}
+
+// Close closes the file system and all nested archives.
+func (n *NestedFS) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var errs []error
+	for p := range n.nested {
+		errs = append(errs, n.remove(p))
+	}
+	if c, ok := n.fsys.(io.Closer); ok {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// nestedDir is an open directory of a NestedFS, which is also a file system
+// rooted at the directory.
+type nestedDir struct {
+	fsys *NestedFS
+	path string
+	info fs.FileInfo
+}
+
+func (d *nestedDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *nestedDir) Close() error               { return nil }
+
+func (d *nestedDir) Read(p []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.path, Err: errors.New("is a directory")}
+}
+
+func (d *nestedDir) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	return d.fsys.Open(path.Join(d.path, name))
+}
+
+// Prefetch announces that the named files, relative to the directory,
+// are about to be read, as for NestedFS.
+func (d *nestedDir) Prefetch(names []string) {
+	d.fsys.Prefetch(joinAll(d.path, names))
+}
+
+func (d *nestedDir) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	return d.fsys.ReadDir(path.Join(d.path, name))
+}
@@ -0,0 +1,66 @@
+package archived
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// zipOf returns a zip archive of files, written in order.
+func zipOf(t *testing.T, files [][2]string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: f[0], Method: zip.Store})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(f[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestNestedFSBudget(t *testing.T) {
+	assert := assert.New(t)
+
+	caseA := string(tgz(t, seriesFiles))
+	caseB := string(tgz(t, seriesFiles[:2]))
+	name := writeTemp(t, "cases.zip", zipOf(t, [][2]string{
+		{"caseA.tgz", caseA},
+		{"caseB.tgz", caseB},
+	}))
+	budget := uint64(max(len(caseA), len(caseB)) + 16)
+	for _, lazy := range []bool{true, false} {
+		var fsys fs.FS
+		var err error
+		if lazy {
+			fsys, err = NewLazyFS(name, budget)
+		} else {
+			fsys, err = NewFS(name)
+		}
+		assert.Nil(err)
+		n := NewNestedFS(fsys, lazy, budget)
+
+		for _, p := range []string{"caseA.tgz", "caseB.tgz"} {
+			b, err := fs.ReadFile(n, p+"/0/data.csv")
+			assert.Nil(err)
+			assert.Equal(seriesFiles[0][1], string(b))
+			assert.Contains(n.nested, p)
+		}
+		assert.NotContains(n.nested, "caseA.tgz", "least recently used archive should be evicted")
+		if lazy {
+			assert.LessOrEqual(n.Size(), budget)
+			assert.LessOrEqual(n.cache.total(), budget)
+		}
+		assert.Nil(n.Close())
+		assert.Zero(n.cache.total())
+	}
+}
@@ -14,6 +14,9 @@ var (
 var (
 	// Verbose controls log output.
 	Verbose bool
+	// Workers is the default number of workers used for reading
+	// files concurrently.
+	Workers int = 1
 )
 
 // Multiple-byte unit constants.
@@ -1,6 +1,10 @@
 package common
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 func FormatByte(b uint64) string {
 	switch {
@@ -15,3 +19,32 @@ func FormatByte(b uint64) string {
 	}
 	return fmt.Sprintf("%4v TiB", b/Tebi)
 }
+
+// ParseByte parses a byte size, e.g., '512MiB', '2 GiB' or '1024'.
+// The supported units are 'B', 'KiB', 'MiB', 'GiB' and 'TiB',
+// and bytes are assumed if no unit is given.
+func ParseByte(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	units := []struct {
+		suffix string
+		mult   uint64
+	}{
+		{"KiB", Kibi},
+		{"MiB", Mebi},
+		{"GiB", Gibi},
+		{"TiB", Tebi},
+		{"B", 1},
+	}
+	mult := uint64(1)
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			s, mult = strings.TrimSpace(strings.TrimSuffix(s, u.suffix)), u.mult
+			break
+		}
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return v * mult, nil
+}
@@ -2,27 +2,55 @@ package dat
 
 import (
 	"bufio"
-	"bytes"
 	"errors"
 	"io"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 )
 
-var errInvalidDelim = errors.New("dat: invalid comment delimiter")
+var (
+	errInvalidDelim = errors.New("dat: invalid comment delimiter")
+
+	// ErrNoHeader is returned by ReadHeader if no comment lines precede
+	// the first record.
+	ErrNoHeader = errors.New("dat: no header")
+	// ErrTuple is returned when a record contains unbalanced parentheses.
+	ErrTuple = errors.New("dat: unbalanced parentheses")
+)
 
 func validDelim(r rune) bool {
 	return r != 0 && r != '"' && r != '\r' && r != '\n' && utf8.ValidRune(r) && r != utf8.RuneError
 }
 
+// A Shape describes the structure of a single field of a record.
+// A scalar has a nil Shape, while a tuple's Shape holds the Shapes of its
+// elements, e.g., the Shape of the vector '(1 2 3)' is Shape{nil, nil, nil},
+// and the Shape of '((1 2 3) (4 5 6))' is Shape{{nil, nil, nil}, {nil, nil, nil}}.
+type Shape []Shape
+
+// Len returns the number of scalar components of s.
+func (s Shape) Len() int {
+	if s == nil {
+		return 1
+	}
+	n := 0
+	for _, e := range s {
+		n += e.Len()
+	}
+	return n
+}
+
 // A Reader reads records from an OpenFOAM DAT file.
 //
 // As returned by NewReader, a Reader expects input conforming to the usual,
 // albeit variable, OpenFOAM DAT file format: fields delimited by whitespace,
 // with or without leading whitespace, which is always ignored, and
 // with lines beggining with '#' denoting comments.
-// All parentheses are automatically removed while reading, hence tensors
-// (of any order) will yield their component values as individual fields.
+// Parenthesised tuples, e.g., vectors and tensors (of any order), are
+// flattened while reading, hence they will yield their component values as
+// individual fields. The structure of the fields of the most recently read
+// record is reported by Shapes.
 //
 // The exported fields can be changed to customize the details before the
 // first call to Read or ReadAll.
@@ -43,6 +71,14 @@ type Reader struct {
 
 	// rawBuffer is a line buffer only used by the readLine method.
 	rawBuffer []byte
+
+	// pending is the first record line, read while reading the leading
+	// comments, which has not yet been parsed.
+	pending    []byte
+	hasPending bool
+
+	// shapes are the field shapes of the most recently read record.
+	shapes []Shape
 }
 
 // NewReader returns a new Reader that reads from r.
@@ -78,6 +114,127 @@ func (r *Reader) ReadAll() (records [][]string, err error) {
 	}
 }
 
+// Shapes returns the shapes of the fields of the most recently read record,
+// i.e., one Shape for each scalar or (possibly nested) tuple in the record.
+// The result is only valid until the next call to Read or ReadAll.
+func (r *Reader) Shapes() []Shape {
+	return r.shapes
+}
+
+// ReadComments reads all comment lines preceding the first record and
+// returns them, in order, with the leading Comment character and the
+// trailing endline removed. Empty lines are skipped.
+// ReadComments must be called before the first call to Read or ReadAll.
+func (r *Reader) ReadComments() ([]string, error) {
+	if r.Comment != 0 && !validDelim(r.Comment) {
+		return nil, errInvalidDelim
+	}
+	var comments []string
+	for {
+		line, errRead := r.readLine()
+		if errRead != nil && errRead != io.EOF {
+			return nil, errRead
+		}
+		if r.Comment != 0 && nextRune(line) == r.Comment {
+			_, n := utf8.DecodeRune(line)
+			comments = append(comments, string(line[n:len(line)-lengthNL(line)]))
+		} else if len(line) != lengthNL(line) {
+			r.pending = append(r.pending[:0], line...)
+			r.hasPending = true
+			break
+		}
+		if errRead == io.EOF {
+			break
+		}
+	}
+	return comments, nil
+}
+
+// ReadHeader reads the header, i.e., the last comment line preceding
+// the first record, and returns its fields. OpenFOAM functionObjects
+// usually write headers in this manner, e.g.:
+//
+//	# Force coefficients
+//	#  Time  Cd  Cl
+//
+// If the last comment line has fewer fields than the first record,
+// the header is the last comment line which has as many fields as the first
+// record. If the last comment line holds a single field, it replaces
+// the first field of the header, since OpenFOAM probes write headers
+// in this manner, e.g.:
+//
+//	# Probe 0 (0 0 0)
+//	# Probe 1 (1 0 0)
+//	#  Probe  0  1
+//	#  Time
+//
+// yields ["Time" "0" "1"].
+//
+// ReadHeader returns ErrNoHeader if no comment lines precede the first
+// record. ReadHeader must be called before the first call to Read or ReadAll.
+func (r *Reader) ReadHeader() ([]string, error) {
+	comments, err := r.ReadComments()
+	if err != nil {
+		return nil, err
+	}
+	if len(comments) == 0 {
+		return nil, ErrNoHeader
+	}
+	header := splitHeader(comments[len(comments)-1])
+	if !r.hasPending {
+		return header, nil
+	}
+	_, shapes, err := parseRecord(string(r.pending))
+	if err != nil || len(header) >= len(shapes) {
+		return header, nil
+	}
+	for i := len(comments) - 2; i >= 0; i-- {
+		h := splitHeader(comments[i])
+		if len(h) != len(shapes) {
+			continue
+		}
+		if len(header) == 1 {
+			h[0] = header[0]
+		}
+		return h, nil
+	}
+	return header, nil
+}
+
+// splitHeader splits a header line into whitespace-separated fields,
+// keeping parenthesised groups together with the preceding field, e.g.,
+// 'Time forces(pressure viscous)' yields ["Time" "forces(pressure viscous)"].
+func splitHeader(s string) []string {
+	var fields []string
+	var b strings.Builder
+	depth := 0
+	flush := func() {
+		if b.Len() > 0 {
+			fields = append(fields, b.String())
+			b.Reset()
+		}
+	}
+	for _, c := range s {
+		switch {
+		case c == '(':
+			if depth == 0 && b.Len() == 0 && len(fields) > 0 {
+				// attach to the preceding field
+				b.WriteString(fields[len(fields)-1])
+				fields = fields[:len(fields)-1]
+			}
+			depth++
+		case c == ')':
+			depth--
+		case unicode.IsSpace(c) && depth <= 0:
+			flush()
+			continue
+		}
+		b.WriteRune(c)
+	}
+	flush()
+	return fields
+}
+
 // readLine reads the next line (with the trailing endline).
 // If EOF is hit without a trailing endline, it will be omitted.
 // If some bytes were read, then the error is never io.EOF.
@@ -127,35 +284,84 @@ func (r *Reader) readRecord() ([]string, error) {
 		return nil, errInvalidDelim
 	}
 
-	// Read line (automatically skipping past empty lines and any comments).
+	// Read line (automatically skipping past empty lines and any comments),
+	// unless a line is pending from reading the leading comments.
 	var line []byte
-	var errRead error
-	for errRead == nil {
-		line, errRead = r.readLine()
-		if r.Comment != 0 && nextRune(line) == r.Comment {
-			line = nil
-			continue // Skip comment lines
+	if r.hasPending {
+		line, r.hasPending = r.pending, false
+	} else {
+		var errRead error
+		for errRead == nil {
+			line, errRead = r.readLine()
+			if r.Comment != 0 && nextRune(line) == r.Comment {
+				line = nil
+				continue // Skip comment lines
+			}
+			if errRead == nil && len(line) == lengthNL(line) {
+				line = nil
+				continue // Skip empty lines
+			}
+			break
 		}
-		if errRead == nil && len(line) == lengthNL(line) {
-			line = nil
-			continue // Skip empty lines
+		if errRead == io.EOF {
+			return nil, errRead
 		}
-		break
 	}
-	if errRead == io.EOF {
-		return nil, errRead
+
+	record, shapes, err := parseRecord(string(line))
+	if err != nil {
+		return nil, err
 	}
+	r.shapes = shapes
+	return record, nil
+}
 
-	// Remove parenthesis
-	s := bytes.Map(
-		func(r rune) rune {
-			if r == '(' || r == ')' {
-				return -1
+// parseRecord splits a line into whitespace-separated fields, flattening
+// any parenthesised tuples, and returns the fields along with the shapes
+// of the record's (unflattened) fields.
+func parseRecord(line string) ([]string, []Shape, error) {
+	var record []string
+	var shapes []Shape
+	var stack []Shape // the tuples currently being parsed
+	start := -1       // the start of the current field
+	add := func(s Shape) {
+		if len(stack) == 0 {
+			shapes = append(shapes, s)
+		} else {
+			stack[len(stack)-1] = append(stack[len(stack)-1], s)
+		}
+	}
+	flush := func(end int) {
+		if start != -1 {
+			record = append(record, line[start:end])
+			add(nil)
+			start = -1
+		}
+	}
+	for i, c := range line {
+		switch {
+		case c == '(':
+			flush(i)
+			stack = append(stack, Shape{})
+		case c == ')':
+			flush(i)
+			if len(stack) == 0 {
+				return nil, nil, ErrTuple
 			}
-			return r
-		},
-		line,
-	)
-
-	return strings.Fields(string(s)), nil
+			t := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			add(t)
+		case unicode.IsSpace(c):
+			flush(i)
+		default:
+			if start == -1 {
+				start = i
+			}
+		}
+	}
+	flush(len(line))
+	if len(stack) != 0 {
+		return nil, nil, ErrTuple
+	}
+	return record, shapes, nil
 }
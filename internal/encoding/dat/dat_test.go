@@ -346,3 +346,176 @@ func TestReadAll(t *testing.T) {
 		})
 	}
 }
+
+type readHeaderTest struct {
+	Name   string
+	Error  error
+	Input  string
+	Header []string
+	Output [][]string
+}
+
+var readHeaderTests = []readHeaderTest{
+	{
+		Name:   "good-header",
+		Error:  nil,
+		Input:  "# t x y\n0 1 2\n",
+		Header: []string{"t", "x", "y"},
+		Output: [][]string{{"0", "1", "2"}},
+	},
+	{
+		Name:  "good-header-foam-probes",
+		Error: nil,
+		Input: `
+# Probe 0 (0 0 0)
+# Probe 1 (1 0 0)
+
+#       Probe             0             1
+#        Time
+0.1	1	2
+0.2	3	4
+`,
+		Header: []string{"Time", "0", "1"},
+		Output: [][]string{{"0.1", "1", "2"}, {"0.2", "3", "4"}},
+	},
+	{
+		Name:  "good-header-foam-probes-vector",
+		Error: nil,
+		Input: `
+# Probe 0 (0 0 0)
+# Probe 1 (1 0 0)
+#       Probe             0             1
+#        Time
+0.1	(1 2 3)	(4 5 6)
+`,
+		Header: []string{"Time", "0", "1"},
+		Output: [][]string{{"0.1", "1", "2", "3", "4", "5", "6"}},
+	},
+	{
+		Name:  "good-header-field-count",
+		Error: nil,
+		Input: `
+#	Time	Cd	Cl
+# trailing comment
+0.1	1	2
+`,
+		Header: []string{"Time", "Cd", "Cl"},
+		Output: [][]string{{"0.1", "1", "2"}},
+	},
+	{
+		Name:  "good-header-w-comments-in-body",
+		Error: nil,
+		Input: `
+# Force coefficients
+#	Time	Cd	Cl
+0.1	1	2
+# a comment which should be skipped
+0.2	3	4
+`,
+		Header: []string{"Time", "Cd", "Cl"},
+		Output: [][]string{{"0.1", "1", "2"}, {"0.2", "3", "4"}},
+	},
+	{
+		Name:   "good-header-only",
+		Error:  nil,
+		Input:  "# t x y",
+		Header: []string{"t", "x", "y"},
+		Output: nil,
+	},
+	{
+		Name:   "good-header-tuple-names",
+		Error:  nil,
+		Input:  "# Time forces(pressure viscous) moment (pressure viscous)\n",
+		Header: []string{"Time", "forces(pressure viscous)", "moment(pressure viscous)"},
+		Output: nil,
+	},
+	{
+		Name:   "bad-no-header",
+		Error:  ErrNoHeader,
+		Input:  "\n0 1 2\n",
+		Header: nil,
+		Output: [][]string{{"0", "1", "2"}},
+	},
+}
+
+func TestReadHeader(t *testing.T) {
+	for _, tt := range readHeaderTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			r := NewReader(strings.NewReader(tt.Input))
+
+			hdr, err := r.ReadHeader()
+			assert.Equal(tt.Error, err)
+			assert.Equal(tt.Header, hdr)
+
+			out, err := r.ReadAll()
+			assert.Nil(err)
+			assert.Equal(tt.Output, out)
+		})
+	}
+}
+
+type shapesTest struct {
+	Name   string
+	Error  error
+	Input  string
+	Output []Shape
+}
+
+var shapesTests = []shapesTest{
+	{
+		Name:   "good-scalar",
+		Error:  nil,
+		Input:  "t x",
+		Output: []Shape{nil, nil},
+	},
+	{
+		Name:   "good-vector",
+		Error:  nil,
+		Input:  "t\t(x y z)",
+		Output: []Shape{nil, {nil, nil, nil}},
+	},
+	{
+		Name:  "good-nested",
+		Error: nil,
+		Input: "t ((x y z) (x y z))\t(x y)",
+		Output: []Shape{
+			nil,
+			{{nil, nil, nil}, {nil, nil, nil}},
+			{nil, nil},
+		},
+	},
+	{
+		Name:   "good-touching",
+		Error:  nil,
+		Input:  "t((x y)(x y))",
+		Output: []Shape{nil, {{nil, nil}, {nil, nil}}},
+	},
+	{
+		Name:   "bad-unclosed",
+		Error:  ErrTuple,
+		Input:  "t ((x y z) (x y z)",
+		Output: nil,
+	},
+	{
+		Name:   "bad-unopened",
+		Error:  ErrTuple,
+		Input:  "t (x y z))",
+		Output: nil,
+	},
+}
+
+func TestShapes(t *testing.T) {
+	for _, tt := range shapesTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			r := NewReader(strings.NewReader(tt.Input))
+
+			_, err := r.Read()
+			assert.Equal(tt.Error, err)
+			if tt.Error == nil {
+				assert.Equal(tt.Output, r.Shapes())
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package dat
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrField is returned when a field cannot be written such that it is
+	// read back unchanged, i.e., if it is empty or contains whitespace or
+	// parentheses.
+	ErrField = errors.New("dat: field is empty or contains whitespace or parentheses")
+)
+
+// A Writer writes records to an OpenFOAM DAT file.
+//
+// As returned by NewWriter, a Writer writes records as single lines of
+// whitespace-separated fields, terminated by a newline. Fields are padded
+// to Widths, hence the fields of consecutive records are aligned if Widths
+// are set, e.g., by Align.
+//
+// Writes are buffered, so Flush must eventually be called to ensure that
+// the record is written to the underlying io.Writer. Any errors that
+// occurred should be checked by calling the Error method.
+type Writer struct {
+	// Comment is the comment character used for the header line.
+	// It is set to pound ('#') by NewWriter.
+	Comment rune
+	// Widths are the minimum widths of the fields, in characters.
+	// Fields are left-aligned within their widths.
+	Widths []int
+
+	w *bufio.Writer
+}
+
+// NewWriter returns a new Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		Comment: '#',
+		w:       bufio.NewWriter(w),
+	}
+}
+
+// headerPrefix returns the prefix of the header line.
+func (w *Writer) headerPrefix() string {
+	return string(w.Comment) + " "
+}
+
+// Align sets Widths such that the fields of the header, if not nil,
+// and all records are aligned.
+func (w *Writer) Align(header []string, records [][]string) {
+	w.Widths = w.Widths[:0]
+	update := func(i int, f string) {
+		n := utf8.RuneCountInString(f)
+		if i < len(w.Widths) {
+			w.Widths[i] = max(w.Widths[i], n)
+		} else {
+			w.Widths = append(w.Widths, n)
+		}
+	}
+	for i, f := range header {
+		if i == 0 {
+			f = w.headerPrefix() + f
+		}
+		update(i, f)
+	}
+	for _, r := range records {
+		for i, f := range r {
+			update(i, f)
+		}
+	}
+}
+
+// WriteHeader writes a header line, i.e., a comment line holding
+// the field names, as read by Reader.ReadHeader.
+func (w *Writer) WriteHeader(fields []string) error {
+	if !validDelim(w.Comment) {
+		return errInvalidDelim
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	header := append([]string{w.headerPrefix() + fields[0]}, fields[1:]...)
+	for _, f := range fields {
+		if err := checkField(f); err != nil {
+			return err
+		}
+	}
+	return w.writeRecord(header)
+}
+
+// Write writes a single record to w.
+// A record is a slice of strings with each string being one field.
+// Writes are buffered, so Flush must eventually be called to ensure
+// that the record is written to the underlying io.Writer.
+func (w *Writer) Write(record []string) error {
+	for _, f := range record {
+		if err := checkField(f); err != nil {
+			return err
+		}
+	}
+	return w.writeRecord(record)
+}
+
+func (w *Writer) writeRecord(record []string) error {
+	for i, f := range record {
+		if i > 0 {
+			if err := w.w.WriteByte(' '); err != nil {
+				return err
+			}
+		}
+		if _, err := w.w.WriteString(f); err != nil {
+			return err
+		}
+		if i < len(record)-1 && i < len(w.Widths) {
+			pad := w.Widths[i] - utf8.RuneCountInString(f)
+			if _, err := w.w.WriteString(strings.Repeat(" ", max(pad, 0))); err != nil {
+				return err
+			}
+		}
+	}
+	return w.w.WriteByte('\n')
+}
+
+// checkField checks whether a field is read back unchanged.
+func checkField(f string) error {
+	if f == "" || strings.ContainsFunc(f, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '(' || r == ')'
+	}) {
+		return ErrField
+	}
+	return nil
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+// To check if an error occurred during Flush, call Error.
+func (w *Writer) Flush() {
+	w.w.Flush()
+}
+
+// Error reports any error that has occurred during
+// a previous Write or Flush.
+func (w *Writer) Error() error {
+	_, err := w.w.Write(nil)
+	return err
+}
+
+// WriteAll writes multiple records to w using Write and
+// then calls Flush, returning any error from the Flush.
+func (w *Writer) WriteAll(records [][]string) error {
+	for _, record := range records {
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	return w.w.Flush()
+}
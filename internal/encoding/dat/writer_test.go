@@ -0,0 +1,111 @@
+package dat
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type writeTest struct {
+	Name   string
+	Error  error
+	Align  bool
+	Header []string
+	Input  [][]string
+	Output string
+}
+
+var writeTests = []writeTest{
+	{
+		Name:   "good-empty",
+		Error:  nil,
+		Input:  nil,
+		Output: "",
+	},
+	{
+		Name:   "good-unaligned",
+		Error:  nil,
+		Input:  [][]string{{"0", "1.5"}, {"10", "2"}},
+		Output: "0 1.5\n10 2\n",
+	},
+	{
+		Name:   "good-aligned",
+		Error:  nil,
+		Align:  true,
+		Input:  [][]string{{"0", "1.5", "a"}, {"10", "2", "b"}},
+		Output: "0  1.5 a\n10 2   b\n",
+	},
+	{
+		Name:   "good-header",
+		Error:  nil,
+		Align:  true,
+		Header: []string{"t", "p"},
+		Input:  [][]string{{"0", "1.5"}, {"10", "2"}},
+		Output: "# t p\n0   1.5\n10  2\n",
+	},
+	{
+		Name:   "bad-whitespace",
+		Error:  ErrField,
+		Input:  [][]string{{"a b"}},
+		Output: "",
+	},
+	{
+		Name:   "bad-parentheses",
+		Error:  ErrField,
+		Input:  [][]string{{"(1"}},
+		Output: "",
+	},
+	{
+		Name:   "bad-empty-field",
+		Error:  ErrField,
+		Input:  [][]string{{"1", ""}},
+		Output: "",
+	},
+}
+
+func TestWriteAll(t *testing.T) {
+	for _, tt := range writeTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			var b bytes.Buffer
+			w := NewWriter(&b)
+			if tt.Align {
+				w.Align(tt.Header, tt.Input)
+			}
+			var err error
+			if tt.Header != nil {
+				err = w.WriteHeader(tt.Header)
+			}
+			if err == nil {
+				err = w.WriteAll(tt.Input)
+			}
+			assert.Equal(tt.Error, err)
+			if tt.Error == nil {
+				assert.Nil(w.Error())
+				assert.Equal(tt.Output, b.String())
+			}
+		})
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	assert := assert.New(t)
+	header := []string{"Time", "p", "U_x"}
+	records := [][]string{{"0.1", "1e-05", "-3"}, {"0.25", "NaN", "12.5"}}
+
+	var b bytes.Buffer
+	w := NewWriter(&b)
+	w.Align(header, records)
+	assert.Nil(w.WriteHeader(header))
+	assert.Nil(w.WriteAll(records))
+
+	r := NewReader(strings.NewReader(b.String()))
+	hdr, err := r.ReadHeader()
+	assert.Nil(err)
+	assert.Equal(header, hdr)
+	out, err := r.ReadAll()
+	assert.Nil(err)
+	assert.Equal(records, out)
+}
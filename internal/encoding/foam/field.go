@@ -0,0 +1,166 @@
+package foam
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// nComps maps OpenFOAM primitive type names to their component counts.
+var nComps = map[string]int{
+	"scalar":          1,
+	"label":           1,
+	"sphericalTensor": 1,
+	"vector":          3,
+	"symmTensor":      6,
+	"tensor":          9,
+}
+
+// A Field holds the values of a field entry, e.g., 'internalField'
+// or a boundary patch 'value'.
+type Field struct {
+	// Uniform is true if the field is uniform, i.e., defined by
+	// a single value.
+	Uniform bool
+	// NComp is the number of components of each value, i.e., 1 for
+	// scalars, 3 for vectors, and so on.
+	NComp int
+	// Values holds the field values, i.e., NComp values for each value.
+	Values []float64
+}
+
+// Len returns the number of values of f.
+func (f *Field) Len() int {
+	if f.NComp == 0 {
+		return 0
+	}
+	return len(f.Values) / f.NComp
+}
+
+// ParseField parses field entry tokens, i.e., of the form
+//
+//	uniform <value>
+//	nonuniform List<type> <n> ( <value> <value> ... )
+//	nonuniform List<type> <n> { <value> }
+//
+// where a value is either a scalar, e.g., '1', or a tuple, e.g., '(1 2 3)'.
+func ParseField(tokens []string) (*Field, error) {
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: empty field entry", ErrSyntax)
+	}
+	switch tokens[0] {
+	case "uniform":
+		f := &Field{Uniform: true}
+		v, rest, err := parseValue(tokens[1:])
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) != 0 {
+			return nil, fmt.Errorf("%w: trailing tokens in uniform value", ErrSyntax)
+		}
+		f.NComp, f.Values = len(v), v
+		return f, nil
+	case "nonuniform":
+		return parseList(tokens[1:])
+	}
+	// a bare value is a uniform value in old file versions
+	return ParseField(append([]string{"uniform"}, tokens...))
+}
+
+// parseList parses a (possibly compact) typed list.
+func parseList(tokens []string) (*Field, error) {
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: missing list", ErrSyntax)
+	}
+	typ, found := strings.CutPrefix(tokens[0], "List<")
+	if !found || !strings.HasSuffix(typ, ">") {
+		return nil, fmt.Errorf("%w: bad list type %q", ErrSyntax, tokens[0])
+	}
+	typ = strings.TrimSuffix(typ, ">")
+	nComp, found := nComps[typ]
+	if !found {
+		return nil, fmt.Errorf("%w: list type %q", ErrUnsupported, typ)
+	}
+	tokens = tokens[1:]
+
+	n := -1
+	if len(tokens) > 0 && tokens[0] != "(" {
+		var err error
+		if n, err = strconv.Atoi(tokens[0]); err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: bad list size %q", ErrSyntax, tokens[0])
+		}
+		tokens = tokens[1:]
+	}
+	if len(tokens) < 2 {
+		return nil, fmt.Errorf("%w: missing list values", ErrSyntax)
+	}
+	f := &Field{NComp: nComp}
+	switch tokens[0] {
+	case "{": // compact list, i.e., n copies of a single value
+		v, rest, err := parseValue(tokens[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 || len(v) != nComp || len(rest) != 1 || rest[0] != "}" {
+			return nil, fmt.Errorf("%w: bad compact list", ErrSyntax)
+		}
+		f.Values = make([]float64, 0, n*nComp)
+		for i := 0; i < n; i++ {
+			f.Values = append(f.Values, v...)
+		}
+	case "(":
+		if n > 0 {
+			f.Values = make([]float64, 0, n*nComp)
+		}
+		rest := tokens[1:]
+		for len(rest) > 0 && rest[0] != ")" {
+			var v []float64
+			var err error
+			if v, rest, err = parseValue(rest); err != nil {
+				return nil, err
+			}
+			if len(v) != nComp {
+				return nil, fmt.Errorf("%w: expected %v components, got %v",
+					ErrSyntax, nComp, len(v))
+			}
+			f.Values = append(f.Values, v...)
+		}
+		if len(rest) != 1 {
+			return nil, fmt.Errorf("%w: unterminated list", ErrSyntax)
+		}
+		if n >= 0 && f.Len() != n {
+			return nil, fmt.Errorf("%w: expected %v list values, got %v",
+				ErrSyntax, n, f.Len())
+		}
+	default:
+		return nil, fmt.Errorf("%w: unexpected %q", ErrSyntax, tokens[0])
+	}
+	return f, nil
+}
+
+// parseValue parses a single scalar or tuple value from the start of
+// tokens and returns the value and the remaining tokens.
+func parseValue(tokens []string) ([]float64, []string, error) {
+	if len(tokens) == 0 {
+		return nil, nil, fmt.Errorf("%w: missing value", ErrSyntax)
+	}
+	if tokens[0] != "(" {
+		v, err := strconv.ParseFloat(tokens[0], 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+		}
+		return []float64{v}, tokens[1:], nil
+	}
+	var v []float64
+	for i := 1; i < len(tokens); i++ {
+		if tokens[i] == ")" {
+			return v, tokens[i+1:], nil
+		}
+		x, err := strconv.ParseFloat(tokens[i], 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+		}
+		v = append(v, x)
+	}
+	return nil, nil, fmt.Errorf("%w: unterminated tuple", ErrSyntax)
+}
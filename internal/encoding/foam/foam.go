@@ -0,0 +1,246 @@
+// Package foam implements decoding of OpenFOAM dictionary files,
+// e.g., field files such as '0/U', in the ASCII format.
+package foam
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrSyntax is returned when the input is malformed.
+	ErrSyntax = errors.New("foam: syntax error")
+	// ErrUnsupported is returned when the input uses features
+	// which are not supported.
+	ErrUnsupported = errors.New("foam: unsupported feature")
+	// ErrNotFound is returned when a dictionary entry does not exist.
+	ErrNotFound = errors.New("foam: entry not found")
+)
+
+// An Entry is a dictionary entry, which is either a sub-dictionary or
+// a primitive entry, i.e., a list of value tokens.
+type Entry struct {
+	// Keyword is the entry keyword.
+	Keyword string
+	// Dict is the sub-dictionary, or nil if the entry is a primitive entry.
+	Dict *Dict
+	// Tokens are the value tokens of a primitive entry.
+	Tokens []string
+}
+
+// A Dict is an OpenFOAM dictionary, i.e., an ordered list of entries.
+type Dict struct {
+	Entries []Entry
+}
+
+// Lookup returns the entry with the keyword key. As in OpenFOAM, later
+// entries take precedence over earlier ones with the same keyword,
+// and quoted keywords, e.g., '"(inlet|outlet)"', are treated as regular
+// expressions, which are only matched if there is no exact match.
+func (d *Dict) Lookup(key string) (*Entry, error) {
+	for i := len(d.Entries) - 1; i >= 0; i-- {
+		if d.Entries[i].Keyword == key {
+			return &d.Entries[i], nil
+		}
+	}
+	for i := len(d.Entries) - 1; i >= 0; i-- {
+		k := d.Entries[i].Keyword
+		if len(k) < 2 || k[0] != '"' || k[len(k)-1] != '"' {
+			continue
+		}
+		re, err := regexp.Compile("^(?:" + k[1:len(k)-1] + ")$")
+		if err == nil && re.MatchString(key) {
+			return &d.Entries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrNotFound, key)
+}
+
+// SubDict returns the sub-dictionary with the keyword key.
+func (d *Dict) SubDict(key string) (*Dict, error) {
+	e, err := d.Lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	if e.Dict == nil {
+		return nil, fmt.Errorf("%w: %q is not a dictionary", ErrSyntax, key)
+	}
+	return e.Dict, nil
+}
+
+// Word returns the value of the single-token primitive entry with
+// the keyword key, without quotes.
+func (d *Dict) Word(key string) (string, error) {
+	e, err := d.Lookup(key)
+	if err != nil {
+		return "", err
+	}
+	if len(e.Tokens) != 1 {
+		return "", fmt.Errorf("%w: %q is not a single token entry", ErrSyntax, key)
+	}
+	return strings.Trim(e.Tokens[0], `"`), nil
+}
+
+// Read reads an OpenFOAM dictionary from r. Comments and directives,
+// e.g., '#include', are skipped and macro expansions are not performed.
+//
+// Files whose header (the 'FoamFile' dictionary) declares the binary
+// format are not supported.
+func Read(r io.Reader) (*Dict, error) {
+	tokens, err := tokenize(r)
+	if err != nil {
+		return nil, err
+	}
+	p := parser{tokens: tokens}
+	d, err := p.dict(false)
+	if err != nil {
+		return nil, err
+	}
+	if h, err := d.SubDict("FoamFile"); err == nil {
+		if f, err := h.Word("format"); err == nil && f != "ascii" {
+			return nil, fmt.Errorf("%w: %q format", ErrUnsupported, f)
+		}
+	}
+	return d, nil
+}
+
+// isPunct reports whether c is a punctuation character.
+func isPunct(c byte) bool {
+	return strings.IndexByte("(){}[];", c) >= 0
+}
+
+// isSpace reports whether c is a whitespace character.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+// tokenize splits the input into word, string and punctuation tokens.
+func tokenize(r io.Reader) ([]string, error) {
+	in, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var tokens []string
+	for i := 0; i < len(in); {
+		c := in[i]
+		switch {
+		case isSpace(c):
+			i++
+		case c == '/' && i+1 < len(in) && in[i+1] == '/':
+			for i < len(in) && in[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(in) && in[i+1] == '*':
+			end := bytes.Index(in[i+2:], []byte("*/"))
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated comment", ErrSyntax)
+			}
+			i += end + 4
+		case c == '#' && i+1 < len(in) && in[i+1] == '{':
+			end := bytes.Index(in[i+2:], []byte("#}"))
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated code block", ErrSyntax)
+			}
+			i += end + 4
+		case c == '#':
+			for i < len(in) && in[i] != '\n' {
+				i++
+			}
+		case isPunct(c):
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(in) && in[j] != '"' {
+				if in[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(in) {
+				return nil, fmt.Errorf("%w: unterminated string", ErrSyntax)
+			}
+			tokens = append(tokens, string(in[i:j+1]))
+			i = j + 1
+		default:
+			j := i
+			for j < len(in) && !isSpace(in[j]) && !isPunct(in[j]) && in[j] != '"' {
+				j++
+			}
+			tokens = append(tokens, string(in[i:j]))
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+// parser is a dictionary parser.
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+// dict parses dictionary entries, until a closing brace if sub is true,
+// or until the end of the input otherwise.
+func (p *parser) dict(sub bool) (*Dict, error) {
+	d := &Dict{}
+	for {
+		if p.pos >= len(p.tokens) {
+			if sub {
+				return nil, fmt.Errorf("%w: unterminated dictionary", ErrSyntax)
+			}
+			return d, nil
+		}
+		kw := p.tokens[p.pos]
+		p.pos++
+		switch {
+		case kw == "}" && sub:
+			return d, nil
+		case kw == ";":
+			continue
+		case isPunct(kw[0]):
+			return nil, fmt.Errorf("%w: unexpected %q", ErrSyntax, kw)
+		}
+		if p.pos < len(p.tokens) && p.tokens[p.pos] == "{" {
+			p.pos++
+			s, err := p.dict(true)
+			if err != nil {
+				return nil, err
+			}
+			d.Entries = append(d.Entries, Entry{Keyword: kw, Dict: s})
+			continue
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, fmt.Errorf("%w: in entry %q", err, kw)
+		}
+		d.Entries = append(d.Entries, Entry{Keyword: kw, Tokens: v})
+	}
+}
+
+// value parses primitive entry tokens up to the terminating semicolon.
+func (p *parser) value() ([]string, error) {
+	start := p.pos
+	depth := 0
+	for ; p.pos < len(p.tokens); p.pos++ {
+		switch p.tokens[p.pos] {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("%w: unbalanced brackets", ErrSyntax)
+			}
+		case ";":
+			if depth == 0 {
+				p.pos++
+				return p.tokens[start : p.pos-1], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: missing ';'", ErrSyntax)
+}
@@ -0,0 +1,207 @@
+package foam
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const fieldFile string = `/*--------------------------------*- C++ -*----------------------------------*\
+  =========                 |
+  \\      /  F ield         | OpenFOAM: The Open Source CFD Toolbox
+\*---------------------------------------------------------------------------*/
+FoamFile
+{
+    version     2.0;
+    format      ascii;
+    class       volVectorField;
+    location    "0.1";
+    object      U;
+}
+// * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * //
+
+#include "initialConditions"
+
+dimensions      [0 1 -1 0 0 0 0];
+
+internalField   nonuniform List<vector>
+3
+(
+(1 0 0)
+(2 0 0)
+(3 0 -1e-3)
+)
+;
+
+boundaryField
+{
+    inlet
+    {
+        type            fixedValue;
+        value           uniform (1 0 0);
+    }
+    "(top|bottom)"
+    {
+        type            slip;
+    }
+    outlet
+    {
+        type            inletOutlet;
+        inletValue      uniform (0 0 0);
+        value           nonuniform List<vector> 2{(4 5 6)};
+    }
+}
+`
+
+func TestRead(t *testing.T) {
+	assert := assert.New(t)
+
+	d, err := Read(strings.NewReader(fieldFile))
+	assert.Nil(err)
+
+	h, err := d.SubDict("FoamFile")
+	assert.Nil(err)
+	obj, err := h.Word("object")
+	assert.Nil(err)
+	assert.Equal("U", obj)
+	loc, err := h.Word("location")
+	assert.Nil(err)
+	assert.Equal("0.1", loc)
+
+	e, err := d.Lookup("dimensions")
+	assert.Nil(err)
+	assert.Equal([]string{"[", "0", "1", "-1", "0", "0", "0", "0", "]"}, e.Tokens)
+
+	b, err := d.SubDict("boundaryField")
+	assert.Nil(err)
+	top, err := b.SubDict("top")
+	assert.Nil(err)
+	typ, err := top.Word("type")
+	assert.Nil(err)
+	assert.Equal("slip", typ)
+
+	_, err = b.Lookup("side")
+	assert.ErrorIs(err, ErrNotFound)
+}
+
+type readErrorTest struct {
+	Name  string
+	Input string
+	Error error
+}
+
+var readErrorTests = []readErrorTest{
+	{
+		Name:  "bad-binary",
+		Input: "FoamFile { format binary; object p; }\n",
+		Error: ErrUnsupported,
+	},
+	{
+		Name:  "bad-semicolon",
+		Input: "internalField uniform 1\n",
+		Error: ErrSyntax,
+	},
+	{
+		Name:  "bad-dictionary",
+		Input: "boundaryField { inlet { type zeroGradient; }\n",
+		Error: ErrSyntax,
+	},
+	{
+		Name:  "bad-comment",
+		Input: "/* internalField uniform 1;\n",
+		Error: ErrSyntax,
+	},
+}
+
+func TestReadError(t *testing.T) {
+	for _, tt := range readErrorTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			d, err := Read(strings.NewReader(tt.Input))
+			assert.ErrorIs(err, tt.Error)
+			assert.Nil(d)
+		})
+	}
+}
+
+type parseFieldTest struct {
+	Name   string
+	Input  string
+	Output *Field
+	Error  error
+}
+
+var parseFieldTests = []parseFieldTest{
+	{
+		Name:   "good-uniform-scalar",
+		Input:  "uniform 1.5",
+		Output: &Field{Uniform: true, NComp: 1, Values: []float64{1.5}},
+		Error:  nil,
+	},
+	{
+		Name:   "good-uniform-vector",
+		Input:  "uniform (1 2 3)",
+		Output: &Field{Uniform: true, NComp: 3, Values: []float64{1, 2, 3}},
+		Error:  nil,
+	},
+	{
+		Name:   "good-nonuniform-scalar",
+		Input:  "nonuniform List<scalar> 3(1 2 3)",
+		Output: &Field{NComp: 1, Values: []float64{1, 2, 3}},
+		Error:  nil,
+	},
+	{
+		Name:   "good-nonuniform-vector",
+		Input:  "nonuniform List<vector> 2((1 2 3) (4 5 6))",
+		Output: &Field{NComp: 3, Values: []float64{1, 2, 3, 4, 5, 6}},
+		Error:  nil,
+	},
+	{
+		Name:   "good-nonuniform-compact",
+		Input:  "nonuniform List<scalar> 2{7}",
+		Output: &Field{NComp: 1, Values: []float64{7, 7}},
+		Error:  nil,
+	},
+	{
+		Name:   "good-nonuniform-empty",
+		Input:  "nonuniform List<scalar> 0()",
+		Output: &Field{NComp: 1, Values: nil},
+		Error:  nil,
+	},
+	{
+		Name:   "bad-list-size",
+		Input:  "nonuniform List<scalar> 3(1 2)",
+		Output: nil,
+		Error:  ErrSyntax,
+	},
+	{
+		Name:   "bad-list-components",
+		Input:  "nonuniform List<vector> 1((1 2))",
+		Output: nil,
+		Error:  ErrSyntax,
+	},
+	{
+		Name:   "bad-list-type",
+		Input:  "nonuniform List<word> 1(a)",
+		Output: nil,
+		Error:  ErrUnsupported,
+	},
+}
+
+func TestParseField(t *testing.T) {
+	for _, tt := range parseFieldTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			d, err := Read(strings.NewReader("field " + tt.Input + ";"))
+			assert.Nil(err)
+			e, err := d.Lookup("field")
+			assert.Nil(err)
+			f, err := ParseField(e.Tokens)
+			assert.ErrorIs(err, tt.Error)
+			assert.Equal(tt.Output, f)
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package vtk
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// legacyReader is a token reader for the legacy VTK format.
+type legacyReader struct {
+	tokens []string
+	pos    int
+}
+
+// next returns the next token, or io.ErrUnexpectedEOF if there are none.
+func (r *legacyReader) next() (string, error) {
+	if r.pos >= len(r.tokens) {
+		return "", io.ErrUnexpectedEOF
+	}
+	r.pos++
+	return r.tokens[r.pos-1], nil
+}
+
+// peek returns the next token without consuming it, or an empty string
+// if there are none.
+func (r *legacyReader) peek() string {
+	if r.pos >= len(r.tokens) {
+		return ""
+	}
+	return r.tokens[r.pos]
+}
+
+// int reads the next token as an integer.
+func (r *legacyReader) int() (int, error) {
+	t, err := r.next()
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.Atoi(t)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrSyntax, err)
+	}
+	return v, nil
+}
+
+// floats reads the next n tokens as floats.
+func (r *legacyReader) floats(n int) ([]float64, error) {
+	if n < 0 || r.pos+n > len(r.tokens) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	v := make([]float64, n)
+	for i := range v {
+		var err error
+		if v[i], err = strconv.ParseFloat(r.tokens[r.pos+i], 64); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+		}
+	}
+	r.pos += n
+	return v, nil
+}
+
+// ints reads the next n tokens as integers.
+func (r *legacyReader) ints(n int) ([]int, error) {
+	if n < 0 || r.pos+n > len(r.tokens) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	v := make([]int, n)
+	for i := range v {
+		var err error
+		if v[i], err = strconv.Atoi(r.tokens[r.pos+i]); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+		}
+	}
+	r.pos += n
+	return v, nil
+}
+
+// cells reads a cell connectivity section, in either the classic format,
+// i.e., a point count followed by point indices for each cell,
+// or the OFFSETS/CONNECTIVITY format introduced in VTK 5.1.
+// The point indices are checked against the number of points nPoints.
+func (r *legacyReader) cells(n, size, nPoints int) ([][]int, error) {
+	if strings.ToUpper(r.peek()) == "OFFSETS" {
+		r.pos += 2 // skip keyword and type
+		offsets, err := r.ints(n)
+		if err != nil {
+			return nil, err
+		}
+		if strings.ToUpper(r.peek()) != "CONNECTIVITY" {
+			return nil, fmt.Errorf("%w: expected CONNECTIVITY", ErrSyntax)
+		}
+		r.pos += 2
+		conn, err := r.ints(size)
+		if err != nil {
+			return nil, err
+		}
+		cells := make([][]int, 0, n)
+		for i := 1; i < len(offsets); i++ {
+			if offsets[i-1] > offsets[i] || offsets[i] > len(conn) {
+				return nil, fmt.Errorf("%w: bad cell offsets", ErrSyntax)
+			}
+			cells = append(cells, conn[offsets[i-1]:offsets[i]])
+		}
+		return cells, checkCells(cells, nPoints)
+	}
+	raw, err := r.ints(size)
+	if err != nil {
+		return nil, err
+	}
+	cells := make([][]int, 0, n)
+	for i := 0; i < len(raw); {
+		m := raw[i]
+		if m < 0 || i+1+m > len(raw) {
+			return nil, fmt.Errorf("%w: bad cell size", ErrSyntax)
+		}
+		cells = append(cells, raw[i+1:i+1+m])
+		i += 1 + m
+	}
+	return cells, checkCells(cells, nPoints)
+}
+
+// legacyTokens splits the input into whitespace-separated tokens,
+// skipping the header (the first three lines) and any METADATA blocks,
+// and returns the tokens along with the header lines.
+func legacyTokens(in io.Reader) ([]string, []string, error) {
+	var header []string
+	var tokens []string
+	metadata := false
+	sc := bufio.NewScanner(in)
+	sc.Buffer(nil, 64*1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case len(header) < 3:
+			header = append(header, line)
+		case metadata:
+			metadata = line != ""
+		case strings.ToUpper(line) == "METADATA":
+			metadata = true
+		default:
+			tokens = append(tokens, strings.Fields(line)...)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(header) < 3 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	return tokens, header, nil
+}
+
+// ReadLegacy reads a VTK dataset, in the legacy ASCII format, from in.
+//
+// The POLYDATA, UNSTRUCTURED_GRID, STRUCTURED_GRID, STRUCTURED_POINTS and
+// RECTILINEAR_GRID dataset types are supported, as are the SCALARS,
+// VECTORS, NORMALS, TENSORS, TEXTURE_COORDINATES, COLOR_SCALARS and FIELD
+// data attributes. Dataset-level FIELD data, i.e., FIELD data preceding
+// POINT_DATA/CELL_DATA, and LOOKUP_TABLE data are skipped.
+// Binary files are not supported.
+func ReadLegacy(in io.Reader) (*Dataset, error) {
+	tokens, header, err := legacyTokens(in)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(strings.ToLower(header[0]), "vtk") {
+		return nil, ErrFormat
+	}
+	if strings.ToUpper(header[2]) != "ASCII" {
+		return nil, fmt.Errorf("%w: %q format", ErrUnsupported, header[2])
+	}
+
+	var d Dataset
+	r := legacyReader{tokens: tokens}
+	var target *[]Array // the current attribute data target
+	var n int           // the current attribute data tuple count
+	var dims [3]int
+	var origin, spacing [3]float64
+	var coords [3][]float64
+	for r.peek() != "" {
+		kw, _ := r.next()
+		switch strings.ToUpper(kw) {
+		case "DATASET":
+			if _, err := r.next(); err != nil {
+				return nil, err
+			}
+		case "POINTS":
+			np, err := r.int()
+			if err != nil {
+				return nil, err
+			}
+			r.pos++ // skip type
+			if d.Points, err = r.floats(3 * np); err != nil {
+				return nil, err
+			}
+		case "DIMENSIONS":
+			for i := range dims {
+				if dims[i], err = r.int(); err != nil {
+					return nil, err
+				}
+			}
+		case "ORIGIN", "SPACING":
+			v, err := r.floats(3)
+			if err != nil {
+				return nil, err
+			}
+			if strings.ToUpper(kw) == "ORIGIN" {
+				copy(origin[:], v)
+			} else {
+				copy(spacing[:], v)
+			}
+		case "X_COORDINATES", "Y_COORDINATES", "Z_COORDINATES":
+			nc, err := r.int()
+			if err != nil {
+				return nil, err
+			}
+			r.pos++ // skip type
+			i := int(strings.ToUpper(kw)[0] - 'X')
+			if coords[i], err = r.floats(nc); err != nil {
+				return nil, err
+			}
+		case "VERTICES", "LINES", "POLYGONS", "TRIANGLE_STRIPS", "CELLS":
+			nc, err := r.int()
+			if err != nil {
+				return nil, err
+			}
+			size, err := r.int()
+			if err != nil {
+				return nil, err
+			}
+			cells, err := r.cells(nc, size, d.NPoints())
+			if err != nil {
+				return nil, err
+			}
+			d.Cells = append(d.Cells, cells...)
+		case "CELL_TYPES":
+			nc, err := r.int()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := r.ints(nc); err != nil {
+				return nil, err
+			}
+		case "POINT_DATA", "CELL_DATA":
+			if n, err = r.int(); err != nil {
+				return nil, err
+			}
+			target = &d.PointData
+			if strings.ToUpper(kw) == "CELL_DATA" {
+				target = &d.CellData
+			}
+		case "SCALARS", "VECTORS", "NORMALS", "TENSORS", "TEXTURE_COORDINATES", "COLOR_SCALARS":
+			if target == nil {
+				return nil, fmt.Errorf("%w: %v outside of POINT_DATA/CELL_DATA", ErrSyntax, kw)
+			}
+			a, err := r.attribute(strings.ToUpper(kw), n)
+			if err != nil {
+				return nil, err
+			}
+			*target = append(*target, a)
+		case "FIELD":
+			arrays, err := r.field()
+			if err != nil {
+				return nil, err
+			}
+			// dataset-level field data, e.g., the 'TimeValue' written by
+			// OpenFOAM, is not associated with points or cells, so discard it
+			if target != nil {
+				*target = append(*target, arrays...)
+			}
+		case "LOOKUP_TABLE":
+			r.pos++ // skip name
+			size, err := r.int()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := r.floats(4 * size); err != nil { // RGBA values
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown keyword %q", ErrSyntax, kw)
+		}
+	}
+
+	// generate points of structured datasets
+	if d.Points == nil && dims[0]*dims[1]*dims[2] > 0 {
+		for i := range coords {
+			if coords[i] == nil {
+				coords[i] = make([]float64, dims[i])
+				for j := range coords[i] {
+					coords[i][j] = origin[i] + float64(j)*spacing[i]
+				}
+			}
+		}
+		d.Points = make([]float64, 0, 3*dims[0]*dims[1]*dims[2])
+		for k := 0; k < dims[2]; k++ {
+			for j := 0; j < dims[1]; j++ {
+				for i := 0; i < dims[0]; i++ {
+					d.Points = append(d.Points, coords[0][i], coords[1][j], coords[2][k])
+				}
+			}
+		}
+	}
+	return &d, nil
+}
+
+// field reads the data arrays of a FIELD block.
+func (r *legacyReader) field() ([]Array, error) {
+	r.pos++ // skip name
+	na, err := r.int()
+	if err != nil {
+		return nil, err
+	}
+	arrays := make([]Array, 0, max(na, 0))
+	for i := 0; i < na; i++ {
+		var a Array
+		if a.Name, err = r.next(); err != nil {
+			return nil, err
+		}
+		if a.NComp, err = r.int(); err != nil {
+			return nil, err
+		}
+		nt, err := r.int()
+		if err != nil {
+			return nil, err
+		}
+		r.pos++ // skip type
+		if a.Values, err = r.floats(a.NComp * nt); err != nil {
+			return nil, err
+		}
+		arrays = append(arrays, a)
+	}
+	return arrays, nil
+}
+
+// attribute reads a dataset attribute of kind kw with n tuples.
+func (r *legacyReader) attribute(kw string, n int) (Array, error) {
+	var a Array
+	var err error
+	if a.Name, err = r.next(); err != nil {
+		return a, err
+	}
+	switch kw {
+	case "SCALARS":
+		r.pos++ // skip type
+		a.NComp = 1
+		if t := r.peek(); t != "" && strings.ToUpper(t) != "LOOKUP_TABLE" {
+			if a.NComp, err = r.int(); err != nil {
+				return a, err
+			}
+		}
+		if strings.ToUpper(r.peek()) == "LOOKUP_TABLE" {
+			r.pos += 2
+		}
+	case "VECTORS", "NORMALS":
+		r.pos++ // skip type
+		a.NComp = 3
+	case "TENSORS":
+		r.pos++ // skip type
+		a.NComp = 9
+	case "TEXTURE_COORDINATES":
+		if a.NComp, err = r.int(); err != nil {
+			return a, err
+		}
+		r.pos++ // skip type
+	case "COLOR_SCALARS":
+		if a.NComp, err = r.int(); err != nil {
+			return a, err
+		}
+	}
+	a.Values, err = r.floats(a.NComp * n)
+	return a, err
+}
@@ -0,0 +1,111 @@
+// Package vtk implements decoding of VTK files, in the legacy ASCII format
+// and in the XML PolyData (VTP) and UnstructuredGrid (VTU) formats,
+// with ASCII or inline binary (base64) encoded data arrays.
+package vtk
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	// ErrFormat is returned when the input is not a VTK file.
+	ErrFormat = errors.New("vtk: unknown file format")
+	// ErrUnsupported is returned when the input uses VTK features
+	// which are not supported.
+	ErrUnsupported = errors.New("vtk: unsupported feature")
+	// ErrSyntax is returned when the input is malformed.
+	ErrSyntax = errors.New("vtk: syntax error")
+)
+
+// An Array is a named data array, e.g., a point or cell field.
+type Array struct {
+	// Name is the array name.
+	Name string
+	// NComp is the number of components of each tuple.
+	NComp int
+	// Values holds the tuple values, i.e., NComp values for each tuple.
+	Values []float64
+}
+
+// NTuples returns the number of tuples of a.
+func (a *Array) NTuples() int {
+	if a.NComp == 0 {
+		return 0
+	}
+	return len(a.Values) / a.NComp
+}
+
+// A Dataset holds the points, cells and data arrays of a VTK dataset.
+type Dataset struct {
+	// Points holds the point coordinates, i.e., three values for each point.
+	Points []float64
+	// Cells holds the point indices of each cell, if the dataset
+	// defines cells.
+	Cells [][]int
+	// PointData holds the data arrays defined at points.
+	PointData []Array
+	// CellData holds the data arrays defined at cells.
+	CellData []Array
+}
+
+// NPoints returns the number of points of d.
+func (d *Dataset) NPoints() int {
+	return len(d.Points) / 3
+}
+
+// checkCells checks whether all point indices of cells are valid
+// indices of n points.
+func checkCells(cells [][]int, n int) error {
+	for i, cell := range cells {
+		for _, p := range cell {
+			if p < 0 || p >= n {
+				return fmt.Errorf("%w: cell %v: point index %v out of range [0, %v)",
+					ErrSyntax, i, p, n)
+			}
+		}
+	}
+	return nil
+}
+
+// CellCenters returns the cell centers, computed as the average of the
+// cell point coordinates, i.e., three values for each cell.
+func (d *Dataset) CellCenters() []float64 {
+	c := make([]float64, 0, 3*len(d.Cells))
+	for _, cell := range d.Cells {
+		var x [3]float64
+		for _, p := range cell {
+			for i := range x {
+				x[i] += d.Points[3*p+i]
+			}
+		}
+		for i := range x {
+			if len(cell) > 0 {
+				x[i] /= float64(len(cell))
+			}
+		}
+		c = append(c, x[:]...)
+	}
+	return c
+}
+
+// Read reads a VTK dataset from r, detecting whether the data is in
+// the legacy or the XML format.
+func Read(r io.Reader) (*Dataset, error) {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(5)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	head = bytes.TrimLeft(head, " \t\r\n\ufeff")
+	switch {
+	case bytes.HasPrefix(head, []byte("# vtk")), bytes.HasPrefix(head, []byte("#vtk")):
+		return ReadLegacy(br)
+	case bytes.HasPrefix(head, []byte("<")):
+		return ReadXML(br)
+	}
+	return nil, ErrFormat
+}
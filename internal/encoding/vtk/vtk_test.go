@@ -0,0 +1,409 @@
+package vtk
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type readTest struct {
+	Name   string
+	Error  error
+	Input  string
+	Output *Dataset
+}
+
+// triangles is the expected output of the two-triangle test datasets.
+var triangles = &Dataset{
+	Points: []float64{0, 0, 0, 1, 0, 0, 1, 1, 0, 0, 1, 0},
+	Cells:  [][]int{{0, 1, 2}, {0, 2, 3}},
+	PointData: []Array{
+		{Name: "p", NComp: 1, Values: []float64{1, 2, 3, 4}},
+		{Name: "U", NComp: 3, Values: []float64{1, 0, 0, 2, 0, 0, 3, 0, 0, 4, 0, 0}},
+	},
+	CellData: []Array{
+		{Name: "id", NComp: 1, Values: []float64{0, 1}},
+	},
+}
+
+var readTests = []readTest{
+	{
+		Name:  "good-legacy",
+		Error: nil,
+		Input: `# vtk DataFile Version 5.1
+two triangles
+ASCII
+DATASET POLYDATA
+POINTS 4 float
+0 0 0 1 0 0
+1 1 0 0 1 0
+POLYGONS 2 8
+3 0 1 2
+3 0 2 3
+POINT_DATA 4
+SCALARS p float
+LOOKUP_TABLE default
+1 2 3 4
+VECTORS U float
+1 0 0 2 0 0 3 0 0 4 0 0
+CELL_DATA 2
+FIELD FieldData 1
+id 1 2 int
+0 1
+METADATA
+INFORMATION 0
+
+`,
+		Output: triangles,
+	},
+	{
+		Name:  "good-legacy-offsets",
+		Error: nil,
+		Input: `# vtk DataFile Version 5.1
+two triangles
+ASCII
+DATASET POLYDATA
+POINTS 4 float
+0 0 0 1 0 0 1 1 0 0 1 0
+POLYGONS 3 6
+OFFSETS vtktypeint64
+0 3 6
+CONNECTIVITY vtktypeint64
+0 1 2 0 2 3
+POINT_DATA 4
+SCALARS p float 1
+LOOKUP_TABLE default
+1 2 3 4
+FIELD FieldData 1
+U 3 4 float
+1 0 0 2 0 0 3 0 0 4 0 0
+CELL_DATA 2
+SCALARS id int
+LOOKUP_TABLE default
+0 1
+`,
+		Output: triangles,
+	},
+	{
+		Name:  "good-legacy-structured-points",
+		Error: nil,
+		Input: `# vtk DataFile Version 3.0
+grid
+ASCII
+DATASET STRUCTURED_POINTS
+DIMENSIONS 2 2 1
+ORIGIN 0 0 0
+SPACING 1 2 1
+POINT_DATA 4
+SCALARS p double
+LOOKUP_TABLE default
+1 2 3 4
+`,
+		Output: &Dataset{
+			Points: []float64{0, 0, 0, 1, 0, 0, 0, 2, 0, 1, 2, 0},
+			PointData: []Array{
+				{Name: "p", NComp: 1, Values: []float64{1, 2, 3, 4}},
+			},
+		},
+	},
+	{
+		Name:  "good-legacy-dataset-field",
+		Error: nil,
+		Input: `# vtk DataFile Version 2.0
+sampled surface
+ASCII
+DATASET POLYDATA
+FIELD FieldData 1
+TimeValue 1 1 float
+0.5
+POINTS 4 float
+0 0 0 1 0 0 1 1 0 0 1 0
+POLYGONS 2 8
+3 0 1 2
+3 0 2 3
+POINT_DATA 4
+SCALARS p float
+LOOKUP_TABLE default
+1 2 3 4
+VECTORS U float
+1 0 0 2 0 0 3 0 0 4 0 0
+CELL_DATA 2
+SCALARS id int 1
+LOOKUP_TABLE default
+0 1
+`,
+		Output: triangles,
+	},
+	{
+		Name:  "good-legacy-lookup-table",
+		Error: nil,
+		Input: `# vtk DataFile Version 3.0
+grid
+ASCII
+DATASET STRUCTURED_POINTS
+DIMENSIONS 2 2 1
+ORIGIN 0 0 0
+SPACING 1 2 1
+POINT_DATA 4
+SCALARS p double
+LOOKUP_TABLE colors
+1 2 3 4
+LOOKUP_TABLE colors 2
+0 0 0 1
+1 1 1 1
+`,
+		Output: &Dataset{
+			Points: []float64{0, 0, 0, 1, 0, 0, 0, 2, 0, 1, 2, 0},
+			PointData: []Array{
+				{Name: "p", NComp: 1, Values: []float64{1, 2, 3, 4}},
+			},
+		},
+	},
+	{
+		Name:  "bad-legacy-binary",
+		Error: ErrUnsupported,
+		Input: `# vtk DataFile Version 3.0
+binary
+BINARY
+DATASET POLYDATA
+`,
+		Output: nil,
+	},
+	{
+		Name:  "bad-legacy-keyword",
+		Error: ErrSyntax,
+		Input: `# vtk DataFile Version 3.0
+bad
+ASCII
+DATASET POLYDATA
+JUNK 3
+`,
+		Output: nil,
+	},
+	{
+		Name:  "bad-legacy-cell-index",
+		Error: ErrSyntax,
+		Input: `# vtk DataFile Version 3.0
+bad
+ASCII
+DATASET POLYDATA
+POINTS 3 float
+0 0 0 1 0 0 1 1 0
+POLYGONS 1 4
+3 0 1 3
+`,
+		Output: nil,
+	},
+	{
+		Name:  "bad-legacy-connectivity-index",
+		Error: ErrSyntax,
+		Input: `# vtk DataFile Version 5.1
+bad
+ASCII
+DATASET POLYDATA
+POINTS 3 float
+0 0 0 1 0 0 1 1 0
+POLYGONS 2 3
+OFFSETS vtktypeint64
+0 3
+CONNECTIVITY vtktypeint64
+0 -1 2
+`,
+		Output: nil,
+	},
+	{
+		Name:  "good-xml-ascii",
+		Error: nil,
+		Input: `<?xml version="1.0"?>
+<VTKFile type="PolyData" version="1.0" byte_order="LittleEndian">
+  <PolyData>
+    <Piece NumberOfPoints="4" NumberOfPolys="2">
+      <PointData>
+        <DataArray type="Float32" Name="p" format="ascii">1 2 3 4</DataArray>
+        <DataArray type="Float32" Name="U" NumberOfComponents="3" format="ascii">
+          1 0 0 2 0 0 3 0 0 4 0 0
+        </DataArray>
+      </PointData>
+      <CellData>
+        <DataArray type="Int32" Name="id" format="ascii">0 1</DataArray>
+      </CellData>
+      <Points>
+        <DataArray type="Float32" NumberOfComponents="3" format="ascii">
+          0 0 0 1 0 0 1 1 0 0 1 0
+        </DataArray>
+      </Points>
+      <Polys>
+        <DataArray type="Int32" Name="connectivity" format="ascii">0 1 2 0 2 3</DataArray>
+        <DataArray type="Int32" Name="offsets" format="ascii">3 6</DataArray>
+      </Polys>
+    </Piece>
+  </PolyData>
+</VTKFile>
+`,
+		Output: triangles,
+	},
+	{
+		Name:  "good-xml-pieces",
+		Error: nil,
+		Input: `<VTKFile type="UnstructuredGrid">
+  <UnstructuredGrid>
+    <Piece NumberOfPoints="2" NumberOfCells="1">
+      <PointData><DataArray type="Float64" Name="p" format="ascii">1 2</DataArray></PointData>
+      <Points><DataArray type="Float64" NumberOfComponents="3" format="ascii">0 0 0 1 0 0</DataArray></Points>
+      <Cells>
+        <DataArray type="Int64" Name="connectivity" format="ascii">0 1</DataArray>
+        <DataArray type="Int64" Name="offsets" format="ascii">2</DataArray>
+        <DataArray type="UInt8" Name="types" format="ascii">3</DataArray>
+      </Cells>
+    </Piece>
+    <Piece NumberOfPoints="2" NumberOfCells="1">
+      <PointData><DataArray type="Float64" Name="p" format="ascii">3 4</DataArray></PointData>
+      <Points><DataArray type="Float64" NumberOfComponents="3" format="ascii">0 1 0 1 1 0</DataArray></Points>
+      <Cells>
+        <DataArray type="Int64" Name="connectivity" format="ascii">0 1</DataArray>
+        <DataArray type="Int64" Name="offsets" format="ascii">2</DataArray>
+        <DataArray type="UInt8" Name="types" format="ascii">3</DataArray>
+      </Cells>
+    </Piece>
+  </UnstructuredGrid>
+</VTKFile>
+`,
+		Output: &Dataset{
+			Points: []float64{0, 0, 0, 1, 0, 0, 0, 1, 0, 1, 1, 0},
+			Cells:  [][]int{{0, 1}, {2, 3}},
+			PointData: []Array{
+				{Name: "p", NComp: 1, Values: []float64{1, 2, 3, 4}},
+			},
+		},
+	},
+	{
+		Name:  "bad-xml-cell-index",
+		Error: ErrSyntax,
+		Input: `<VTKFile type="UnstructuredGrid">
+  <UnstructuredGrid>
+    <Piece NumberOfPoints="2" NumberOfCells="1">
+      <Points><DataArray type="Float64" NumberOfComponents="3" format="ascii">0 0 0 1 0 0</DataArray></Points>
+      <Cells>
+        <DataArray type="Int64" Name="connectivity" format="ascii">0 2</DataArray>
+        <DataArray type="Int64" Name="offsets" format="ascii">2</DataArray>
+      </Cells>
+    </Piece>
+  </UnstructuredGrid>
+</VTKFile>
+`,
+		Output: nil,
+	},
+	{
+		Name:   "bad-xml-appended",
+		Error:  ErrUnsupported,
+		Input:  `<VTKFile type="PolyData"><PolyData><Piece><PointData><DataArray type="Float32" Name="p" format="appended" offset="0"/></PointData></Piece></PolyData></VTKFile>`,
+		Output: nil,
+	},
+	{
+		Name:   "bad-format",
+		Error:  ErrFormat,
+		Input:  `x y z`,
+		Output: nil,
+	},
+}
+
+func TestRead(t *testing.T) {
+	for _, tt := range readTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			d, err := Read(strings.NewReader(tt.Input))
+			assert.ErrorIs(err, tt.Error)
+			assert.Equal(tt.Output, d)
+		})
+	}
+}
+
+// encodeBinary encodes v as VTK inline binary data of type Float64, with
+// a UInt32 header, optionally compressing the data in two blocks.
+func encodeBinary(v []float64, compressed, together bool) string {
+	var data bytes.Buffer
+	for _, x := range v {
+		binary.Write(&data, binary.LittleEndian, math.Float64bits(x))
+	}
+	enc := base64.StdEncoding
+	if !compressed {
+		header := binary.LittleEndian.AppendUint32(nil, uint32(data.Len()))
+		if together {
+			return enc.EncodeToString(append(header, data.Bytes()...))
+		}
+		return enc.EncodeToString(header) + enc.EncodeToString(data.Bytes())
+	}
+	raw := data.Bytes()
+	blockSize := (len(raw) + 1) / 2
+	var blocks [][]byte
+	for len(raw) > 0 {
+		n := min(blockSize, len(raw))
+		var b bytes.Buffer
+		w := zlib.NewWriter(&b)
+		w.Write(raw[:n])
+		w.Close()
+		blocks = append(blocks, b.Bytes())
+		raw = raw[n:]
+	}
+	header := binary.LittleEndian.AppendUint32(nil, uint32(len(blocks)))
+	header = binary.LittleEndian.AppendUint32(header, uint32(blockSize))
+	header = binary.LittleEndian.AppendUint32(header, uint32(data.Len()-blockSize))
+	var body []byte
+	for _, b := range blocks {
+		header = binary.LittleEndian.AppendUint32(header, uint32(len(b)))
+		body = append(body, b...)
+	}
+	return enc.EncodeToString(header) + enc.EncodeToString(body)
+}
+
+func TestReadXMLBinary(t *testing.T) {
+	points := []float64{0, 0, 0, 1, 0, 0, 2, 0, 0}
+	p := []float64{1.5, -2.25, math.Pi}
+	for _, tt := range []struct {
+		Name       string
+		Compressed bool
+		Together   bool
+	}{
+		{Name: "separate", Compressed: false, Together: false},
+		{Name: "together", Compressed: false, Together: true},
+		{Name: "zlib", Compressed: true, Together: false},
+	} {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			compressor := ""
+			if tt.Compressed {
+				compressor = `compressor="vtkZLibDataCompressor"`
+			}
+			input := `<VTKFile type="PolyData" header_type="UInt32" ` + compressor + `>
+<PolyData><Piece NumberOfPoints="3">
+<PointData><DataArray type="Float64" Name="p" format="binary">` +
+				encodeBinary(p, tt.Compressed, tt.Together) + `</DataArray></PointData>
+<Points><DataArray type="Float64" NumberOfComponents="3" format="binary">` +
+				encodeBinary(points, tt.Compressed, tt.Together) + `</DataArray></Points>
+</Piece></PolyData></VTKFile>`
+
+			d, err := ReadXML(strings.NewReader(input))
+			assert.Nil(err)
+			assert.Equal(&Dataset{
+				Points:    points,
+				PointData: []Array{{Name: "p", NComp: 1, Values: p}},
+			}, d)
+		})
+	}
+}
+
+func TestCellCenters(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(
+		[]float64{2.0 / 3.0, 1.0 / 3.0, 0, 1.0 / 3.0, 2.0 / 3.0, 0},
+		triangles.CellCenters(),
+	)
+}
@@ -0,0 +1,372 @@
+package vtk
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// xmlFile is the root element of a VTK XML file.
+type xmlFile struct {
+	Type       string       `xml:"type,attr"`
+	ByteOrder  string       `xml:"byte_order,attr"`
+	HeaderType string       `xml:"header_type,attr"`
+	Compressor string       `xml:"compressor,attr"`
+	Datasets   []xmlDataset `xml:",any"`
+}
+
+// xmlDataset is a dataset element, e.g., PolyData or UnstructuredGrid.
+type xmlDataset struct {
+	XMLName xml.Name
+	Pieces  []xmlPiece `xml:"Piece"`
+}
+
+// xmlPiece is a piece of a dataset.
+type xmlPiece struct {
+	Points    xmlArrays `xml:"Points"`
+	PointData xmlArrays `xml:"PointData"`
+	CellData  xmlArrays `xml:"CellData"`
+	// cell connectivity, in the order in which cell data is stored
+	Verts  xmlArrays `xml:"Verts"`
+	Lines  xmlArrays `xml:"Lines"`
+	Strips xmlArrays `xml:"Strips"`
+	Polys  xmlArrays `xml:"Polys"`
+	Cells  xmlArrays `xml:"Cells"`
+}
+
+// xmlArrays is a list of data arrays.
+type xmlArrays struct {
+	Arrays []xmlDataArray `xml:"DataArray"`
+}
+
+// find returns the data array named name, or nil if there is none.
+func (a *xmlArrays) find(name string) *xmlDataArray {
+	for i := range a.Arrays {
+		if a.Arrays[i].Name == name {
+			return &a.Arrays[i]
+		}
+	}
+	return nil
+}
+
+// xmlDataArray is a single data array.
+type xmlDataArray struct {
+	Type   string `xml:"type,attr"`
+	Name   string `xml:"Name,attr"`
+	NComp  int    `xml:"NumberOfComponents,attr"`
+	Format string `xml:"format,attr"`
+	Data   string `xml:",chardata"`
+}
+
+// xmlDecoder holds the file-wide binary encoding settings.
+type xmlDecoder struct {
+	order      binary.ByteOrder
+	headerSize int
+	compressed bool
+}
+
+// ReadXML reads a VTK dataset, in the XML PolyData or UnstructuredGrid
+// format, from in. Data arrays must be stored inline, either as ASCII
+// or as base64 encoded binary, optionally compressed using zlib.
+// Appended data is not supported.
+func ReadXML(in io.Reader) (*Dataset, error) {
+	var f xmlFile
+	if err := xml.NewDecoder(in).Decode(&f); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+	}
+	dec := xmlDecoder{order: binary.LittleEndian, headerSize: 4}
+	if f.ByteOrder == "BigEndian" {
+		dec.order = binary.BigEndian
+	}
+	switch f.HeaderType {
+	case "", "UInt32":
+	case "UInt64":
+		dec.headerSize = 8
+	default:
+		return nil, fmt.Errorf("%w: header type %q", ErrUnsupported, f.HeaderType)
+	}
+	switch f.Compressor {
+	case "":
+	case "vtkZLibDataCompressor":
+		dec.compressed = true
+	default:
+		return nil, fmt.Errorf("%w: compressor %q", ErrUnsupported, f.Compressor)
+	}
+
+	var ds *xmlDataset
+	for i := range f.Datasets {
+		if f.Datasets[i].XMLName.Local == f.Type {
+			ds = &f.Datasets[i]
+		}
+	}
+	if ds == nil {
+		return nil, ErrFormat
+	}
+	if f.Type != "PolyData" && f.Type != "UnstructuredGrid" {
+		return nil, fmt.Errorf("%w: dataset type %q", ErrUnsupported, f.Type)
+	}
+
+	var d Dataset
+	for _, p := range ds.Pieces {
+		offset := d.NPoints()
+		if a := p.Points.Arrays; len(a) > 0 {
+			pts, err := dec.values(&a[0])
+			if err != nil {
+				return nil, err
+			}
+			d.Points = append(d.Points, pts...)
+		}
+		for _, c := range []xmlArrays{p.Verts, p.Lines, p.Strips, p.Polys, p.Cells} {
+			cells, err := dec.cells(&c, offset, d.NPoints()-offset)
+			if err != nil {
+				return nil, err
+			}
+			d.Cells = append(d.Cells, cells...)
+		}
+		var err error
+		if d.PointData, err = dec.appendArrays(d.PointData, &p.PointData); err != nil {
+			return nil, err
+		}
+		if d.CellData, err = dec.appendArrays(d.CellData, &p.CellData); err != nil {
+			return nil, err
+		}
+	}
+	return &d, nil
+}
+
+// appendArrays decodes the arrays of src and appends their values to the
+// matching arrays of dst, or appends new arrays to dst if there are none.
+func (dec *xmlDecoder) appendArrays(dst []Array, src *xmlArrays) ([]Array, error) {
+	for i := range src.Arrays {
+		x := &src.Arrays[i]
+		v, err := dec.values(x)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for j := range dst {
+			if dst[j].Name == x.Name {
+				dst[j].Values = append(dst[j].Values, v...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			nc := x.NComp
+			if nc == 0 {
+				nc = 1
+			}
+			dst = append(dst, Array{Name: x.Name, NComp: nc, Values: v})
+		}
+	}
+	return dst, nil
+}
+
+// cells decodes the cell connectivity of c, offsetting the point indices
+// by offset. The point indices are checked against the number of points
+// of the piece nPoints.
+func (dec *xmlDecoder) cells(c *xmlArrays, offset, nPoints int) ([][]int, error) {
+	ca, oa := c.find("connectivity"), c.find("offsets")
+	if ca == nil || oa == nil {
+		return nil, nil
+	}
+	conn, err := dec.values(ca)
+	if err != nil {
+		return nil, err
+	}
+	offsets, err := dec.values(oa)
+	if err != nil {
+		return nil, err
+	}
+	cells := make([][]int, 0, len(offsets))
+	start := 0
+	for _, o := range offsets {
+		end := int(o)
+		if end < start || end > len(conn) {
+			return nil, fmt.Errorf("%w: bad cell offsets", ErrSyntax)
+		}
+		cell := make([]int, end-start)
+		for i := range cell {
+			cell[i] = int(conn[start+i])
+		}
+		cells = append(cells, cell)
+		start = end
+	}
+	if err := checkCells(cells, nPoints); err != nil {
+		return nil, err
+	}
+	for _, cell := range cells {
+		for i := range cell {
+			cell[i] += offset
+		}
+	}
+	return cells, nil
+}
+
+// values decodes the values of a data array.
+func (dec *xmlDecoder) values(a *xmlDataArray) ([]float64, error) {
+	switch a.Format {
+	case "ascii":
+		fields := strings.Fields(a.Data)
+		v := make([]float64, len(fields))
+		for i, f := range fields {
+			var err error
+			if v[i], err = strconv.ParseFloat(f, 64); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+			}
+		}
+		return v, nil
+	case "binary":
+		raw, err := dec.binary(strings.Join(strings.Fields(a.Data), ""))
+		if err != nil {
+			return nil, fmt.Errorf("%w: array %q: %v", ErrSyntax, a.Name, err)
+		}
+		return dec.convert(raw, a.Type)
+	}
+	return nil, fmt.Errorf("%w: %q format", ErrUnsupported, a.Format)
+}
+
+// base64Len returns the length of n bytes base64 encoded.
+func base64Len(n int) int {
+	return 4 * ((n + 2) / 3)
+}
+
+// header decodes a binary header value.
+func (dec *xmlDecoder) header(b []byte) uint64 {
+	if dec.headerSize == 8 {
+		return dec.order.Uint64(b)
+	}
+	return uint64(dec.order.Uint32(b))
+}
+
+// binary decodes base64 encoded binary data, stripping the header and
+// decompressing the data if necessary.
+func (dec *xmlDecoder) binary(s string) ([]byte, error) {
+	enc := base64.StdEncoding
+	hs := dec.headerSize
+	if !dec.compressed {
+		// the header may be encoded separately or together with the data
+		n := base64Len(hs)
+		if len(s) < n {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if s[n-1] == '=' {
+			h, err := enc.DecodeString(s[:n])
+			if err != nil {
+				return nil, err
+			}
+			data, err := enc.DecodeString(s[n:])
+			if err != nil {
+				return nil, err
+			}
+			size := dec.header(h)
+			if size > uint64(len(data)) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return data[:size], nil
+		}
+		b, err := enc.DecodeString(s)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) < hs {
+			return nil, io.ErrUnexpectedEOF
+		}
+		size := dec.header(b)
+		if size > uint64(len(b)-hs) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return b[hs : hs+int(size)], nil
+	}
+
+	// the compressed header is [nblocks, blocksize, lastblocksize,
+	// compressedsizes...] and is always encoded separately from the data
+	n := base64Len(hs)
+	if len(s) < n {
+		return nil, io.ErrUnexpectedEOF
+	}
+	h, err := enc.DecodeString(s[:n])
+	if err != nil {
+		return nil, err
+	}
+	nb := int(dec.header(h))
+	n = base64Len((3 + nb) * hs)
+	if nb < 0 || len(s) < n {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if h, err = enc.DecodeString(s[:n]); err != nil {
+		return nil, err
+	}
+	data, err := enc.DecodeString(s[n:])
+	if err != nil {
+		return nil, err
+	}
+	var out []byte
+	start := 0
+	for i := 0; i < nb; i++ {
+		size := int(dec.header(h[(3+i)*hs:]))
+		if size < 0 || start+size > len(data) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(data[start : start+size]))
+		if err != nil {
+			return nil, err
+		}
+		b, err := io.ReadAll(zr)
+		zr.Close()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b...)
+		start += size
+	}
+	return out, nil
+}
+
+// convert converts raw binary data of type typ to float64 values.
+func (dec *xmlDecoder) convert(b []byte, typ string) ([]float64, error) {
+	var size int
+	var get func([]byte) float64
+	o := dec.order
+	switch typ {
+	case "Int8":
+		size, get = 1, func(b []byte) float64 { return float64(int8(b[0])) }
+	case "UInt8":
+		size, get = 1, func(b []byte) float64 { return float64(b[0]) }
+	case "Int16":
+		size, get = 2, func(b []byte) float64 { return float64(int16(o.Uint16(b))) }
+	case "UInt16":
+		size, get = 2, func(b []byte) float64 { return float64(o.Uint16(b)) }
+	case "Int32":
+		size, get = 4, func(b []byte) float64 { return float64(int32(o.Uint32(b))) }
+	case "UInt32":
+		size, get = 4, func(b []byte) float64 { return float64(o.Uint32(b)) }
+	case "Int64":
+		size, get = 8, func(b []byte) float64 { return float64(int64(o.Uint64(b))) }
+	case "UInt64":
+		size, get = 8, func(b []byte) float64 { return float64(o.Uint64(b)) }
+	case "Float32":
+		size, get = 4, func(b []byte) float64 {
+			return float64(math.Float32frombits(o.Uint32(b)))
+		}
+	case "Float64":
+		size, get = 8, func(b []byte) float64 { return math.Float64frombits(o.Uint64(b)) }
+	default:
+		return nil, fmt.Errorf("%w: data type %q", ErrUnsupported, typ)
+	}
+	if len(b)%size != 0 {
+		return nil, fmt.Errorf("%w: bad %v data size: %v", ErrSyntax, typ, len(b))
+	}
+	v := make([]float64, len(b)/size)
+	for i := range v {
+		v[i] = get(b[i*size:])
+	}
+	return v, nil
+}
@@ -5,6 +5,11 @@ import (
 	"io"
 	"io/fs"
 	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/Milover/post/internal/archived"
 	"github.com/Milover/post/internal/common"
@@ -27,12 +32,23 @@ type archive struct {
 	File string `yaml:"file"`
 	// ClearAfterRead toggles whether Archive is cleared after reading.
 	ClearAfterRead bool `yaml:"clear_after_read"`
+	// Lazy toggles whether the archive is read lazily, i.e., whether only
+	// the bodies of the files which are read are loaded into memory,
+	// instead of the entire archive.
+	Lazy bool `yaml:"lazy"`
+	// MemoryBudget is the maximum amount of memory used for storing
+	// archives, e.g., '512MiB'. If it is exceeded, the least recently
+	// used archives, and file bodies of lazily read archives, are evicted
+	// from memory. If left empty, the amount is unlimited.
+	MemoryBudget string `yaml:"memory_budget"`
 	// FormatSpec is the config for the input,
 	// e.g., if a CSV file is to be read from the archive, FromatSpec would
 	// define a config for a CSV input type.
 	FormatSpec Config `yaml:"format_spec"`
 
-	s map[string]fs.FS
+	s      map[string]fs.FS // archives, keyed by file name and read options
+	order  []string         // archive keys, least recently used first
+	budget uint64
 }
 
 func defaultArchive() *archive {
@@ -50,37 +66,87 @@ func NewArchive(n *yaml.Node) (*archive, error) {
 		}
 		Archive = defaultArchive()
 	}
+	// reset options which should not persist between reads
+	Archive.Lazy, Archive.MemoryBudget = false, ""
 	if err := n.Decode(Archive); err != nil {
 		return nil, fmt.Errorf("archive: %w", err)
 	}
 	if Archive.File == "" {
 		return nil, fmt.Errorf("archive: %w: %v", common.ErrUnsetField, "file")
 	}
+	Archive.budget = 0
+	if Archive.MemoryBudget != "" {
+		var err error
+		if Archive.budget, err = common.ParseByte(Archive.MemoryBudget); err != nil {
+			return nil, fmt.Errorf("archive: %w: %q: %v",
+				common.ErrBadFieldValue, "memory_budget", err)
+		}
+	}
 	return Archive, nil
 }
 
+// splitArchivePath splits a (nested) archive path into the path of
+// the archive file and the path within the archive,
+// e.g., 'cases.zip/caseA.tar.xz' into 'cases.zip' and 'caseA.tar.xz'.
+func splitArchivePath(name string) (string, string) {
+	parts := strings.Split(filepath.ToSlash(name), "/")
+	for i := range parts {
+		p := filepath.FromSlash(strings.Join(parts[:i+1], "/"))
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			return p, strings.Join(parts[i+1:], "/")
+		}
+	}
+	return name, ""
+}
+
+// key returns the key of the archive file in the store, which depends
+// on the read options, since they determine how the archive is held
+// in memory.
+func (a *archive) key(file string) string {
+	if !a.Lazy && a.budget == 0 {
+		return file
+	}
+	return fmt.Sprintf("%v (lazy: %v, memory_budget: %v)", file, a.Lazy, a.budget)
+}
+
 // Read reads a dataframe.DataFrame from the archive, using the reader
 // specified by the 'format_spec'.
 func (a *archive) Read() (*dataframe.DataFrame, error) {
-	fsys, ok := a.s[a.File]
+	file, inner := splitArchivePath(a.File)
+	key := a.key(file)
+	fsys, ok := a.s[key]
 	if !ok {
 		if common.Verbose {
-			log.Printf("archive: loading: %v", a.File)
+			log.Printf("archive: loading: %v", file)
 		}
 		var err error
-		fsys, err = archived.NewFS(a.File)
+		if a.Lazy {
+			fsys, err = archived.NewLazyFS(file, a.budget)
+		} else {
+			fsys, err = archived.NewFS(file)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("archive: %w", err)
 		}
-		a.s[a.File] = fsys
+		fsys = archived.NewNestedFS(fsys, a.Lazy, a.budget)
+		a.s[key] = fsys
 	}
+	a.touch(key)
 	fn := func(name string) (io.ReadCloser, error) {
+		if inner != "" {
+			name = path.Join(inner, name)
+		}
 		return fsys.Open(name)
 	}
-	df, err := ReadFromFn(fn, &a.FormatSpec)
+	spec, err := globSpec(&a.FormatSpec)
+	if err != nil {
+		return nil, fmt.Errorf("archive: %w", err)
+	}
+	df, err := ReadFromFn(fn, spec)
 	if err != nil {
 		return nil, fmt.Errorf("archive: %w", err)
 	}
+	a.evict()
 	if a.ClearAfterRead {
 		if common.Verbose {
 			log.Printf("archive: clearing: %q", common.MapKeys(a.s))
@@ -90,6 +156,48 @@ func (a *archive) Read() (*dataframe.DataFrame, error) {
 	return df, nil
 }
 
+// touch marks the archive key as the most recently used.
+func (a *archive) touch(name string) {
+	if i := slices.Index(a.order, name); i >= 0 {
+		a.order = slices.Delete(a.order, i, i+1)
+	}
+	a.order = append(a.order, name)
+}
+
+// evict removes the least recently used archives from memory, except
+// the most recently used one, until the memory budget is met.
+func (a *archive) evict() {
+	if a.budget == 0 {
+		return
+	}
+	var total uint64
+	for _, fsys := range a.s {
+		total += archived.Size(fsys)
+	}
+	for total > a.budget && len(a.order) > 1 {
+		name := a.order[0]
+		if common.Verbose {
+			log.Printf("archive: evicting: %v", name)
+		}
+		total -= archived.Size(a.s[name])
+		a.remove(name)
+	}
+}
+
+// remove removes an archive from memory.
+func (a *archive) remove(name string) {
+	if c, ok := a.s[name].(io.Closer); ok {
+		c.Close()
+	}
+	delete(a.s, name)
+	if i := slices.Index(a.order, name); i >= 0 {
+		a.order = slices.Delete(a.order, i, i+1)
+	}
+}
+
 func (a *archive) Clear() {
+	for name := range a.s {
+		a.remove(name)
+	}
 	*a = *defaultArchive()
 }
@@ -160,6 +160,65 @@ format_spec:
 		Output: expected,
 		Error:  nil,
 	},
+	// nested
+	{
+		Name: "good-csv-nested-file",
+		Config: `
+file: 'testdata/cases.zip/caseA.tar.xz'
+format_spec:
+  type: csv
+  type_spec:
+    file: 'postProcessing/data.csv'
+`,
+		Output: expected,
+		Error:  nil,
+	},
+	{
+		Name: "good-csv-nested-path",
+		Config: `
+file: 'testdata/cases.zip'
+format_spec:
+  type: csv
+  type_spec:
+    file: 'caseB.zip/postProcessing/data.csv'
+`,
+		Output: expected,
+		Error:  nil,
+	},
+	{
+		Name: "good-csv-nested-glob",
+		Config: `
+file: 'testdata/cases.zip'
+format_spec:
+  type: csv
+  type_spec:
+    file: '*/postProcessing/data.csv'
+`,
+		Output: dataframe.New(
+			series.New(repeat(6,
+				"caseA.tar.xz/postProcessing/data.csv",
+				"caseB.zip/postProcessing/data.csv",
+			), series.String, "source"),
+			expected.RBind(expected).Col("x"),
+			expected.RBind(expected).Col("y"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-series-nested",
+		Config: `
+file: 'testdata/cases.zip'
+format_spec:
+  type: time-series
+  type_spec:
+    directory: 'caseA.tar.xz/foam_series.good'
+    file: data.csv
+    format_spec:
+      type: csv
+`,
+		Output: expectedSeries,
+		Error:  nil,
+	},
 	// time-series
 	{
 		Name: "good-series-tar.xz",
@@ -251,3 +310,105 @@ func TestArchiveRead(t *testing.T) {
 		})
 	}
 }
+
+func TestArchiveLazyRead(t *testing.T) {
+	for _, tt := range archiveReadTests {
+		for _, opts := range []string{
+			"lazy: true\n",
+			"lazy: true\nmemory_budget: 1B\n", // nothing is cached
+		} {
+			t.Run(tt.Name, func(t *testing.T) {
+				assert := assert.New(t)
+
+				if Archive != nil {
+					Archive.Clear()
+				}
+				raw, err := io.ReadAll(strings.NewReader(tt.Config + opts))
+				assert.Nil(err, "unexpected io.ReadAll() error")
+				var config yaml.Node
+				err = yaml.Unmarshal(raw, &config)
+				assert.Nil(err, "unexpected yaml.Unmarshal() error")
+
+				rw, err := NewArchive(&config)
+				assert.Nil(err, "unexpected NewArchive() error")
+				out, err := rw.Read()
+
+				assert.Equal(tt.Error, err)
+				if tt.Error != nil {
+					assert.Nil(out)
+				} else {
+					assert.Equal(tt.Output, *out)
+				}
+			})
+		}
+	}
+	Archive.Clear()
+}
+
+func TestArchiveMemoryBudget(t *testing.T) {
+	assert := assert.New(t)
+
+	if Archive != nil {
+		Archive.Clear()
+	}
+	read := func(file string) {
+		config := `
+file: '` + file + `'
+memory_budget: 40B
+format_spec:
+  type: csv
+  type_spec:
+    file: 'data.csv'
+`
+		var n yaml.Node
+		err := yaml.Unmarshal([]byte(config), &n)
+		assert.Nil(err, "unexpected yaml.Unmarshal() error")
+		rw, err := NewArchive(&n)
+		assert.Nil(err, "unexpected NewArchive() error")
+		out, err := rw.Read()
+		assert.Nil(err)
+		assert.Equal(expected, *out)
+	}
+	read("testdata/data.csv.tar")
+	assert.Contains(Archive.s, Archive.key("testdata/data.csv.tar"))
+	read("testdata/data.csv.zip")
+	assert.NotContains(Archive.s, Archive.key("testdata/data.csv.tar"))
+	assert.Contains(Archive.s, Archive.key("testdata/data.csv.zip"))
+	Archive.Clear()
+}
+
+func TestArchiveOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	if Archive != nil {
+		Archive.Clear()
+	}
+	read := func(opts string) string {
+		config := `
+file: 'testdata/data.csv.tgz'
+format_spec:
+  type: csv
+  type_spec:
+    file: 'data.csv'
+` + opts
+		var n yaml.Node
+		err := yaml.Unmarshal([]byte(config), &n)
+		assert.Nil(err, "unexpected yaml.Unmarshal() error")
+		rw, err := NewArchive(&n)
+		assert.Nil(err, "unexpected NewArchive() error")
+		out, err := rw.Read()
+		assert.Nil(err)
+		assert.Equal(expected, *out)
+		return rw.key("testdata/data.csv.tgz")
+	}
+	keys := []string{
+		read("lazy: true\n"),
+		read(""),
+		read("lazy: true\nmemory_budget: 1KiB\n"),
+	}
+	assert.Len(Archive.s, len(keys), "archives read with different options should be kept apart")
+	for _, key := range keys {
+		assert.Contains(Archive.s, key)
+	}
+	Archive.Clear()
+}
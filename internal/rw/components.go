@@ -0,0 +1,137 @@
+package rw
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Milover/post/internal/common"
+	datenc "github.com/Milover/post/internal/encoding/dat"
+)
+
+// Component suffix schemes.
+const (
+	// SuffixXYZ names vector components 'x', 'y', 'z'. Components of
+	// tuples of any other size are named by their index.
+	SuffixXYZ string = "xyz"
+	// SuffixXYZTensor names vector components as SuffixXYZ, and
+	// (symmetric) tensor components 'xx', 'xy', ..., 'zz'. Components of
+	// tuples of any other size are named by their index.
+	SuffixXYZTensor string = "xyz-tensor"
+	// SuffixIndex names tuple components by their index, i.e.,
+	// '0', '1', '2', ...
+	SuffixIndex string = "index"
+)
+
+var (
+	suffixesXYZ = map[int][]string{
+		3: {"x", "y", "z"},
+	}
+	suffixesXYZTensor = map[int][]string{
+		3: {"x", "y", "z"},
+		6: {"xx", "xy", "xz", "yy", "yz", "zz"},
+		9: {"xx", "xy", "xz", "yx", "yy", "yz", "zx", "zy", "zz"},
+	}
+)
+
+// componentSpec defines how the components of tuple valued fields,
+// e.g., vectors and tensors, are named.
+type componentSpec struct {
+	// ComponentSuffix is the suffix scheme used for naming components,
+	// one of 'xyz', 'xyz-tensor' or 'index'.
+	ComponentSuffix string `yaml:"component_suffix"`
+	// ComponentSeparator separates a field name from a component suffix.
+	ComponentSeparator string `yaml:"component_separator"`
+}
+
+func defaultComponentSpec() componentSpec {
+	return componentSpec{
+		ComponentSuffix:    SuffixXYZ,
+		ComponentSeparator: "_",
+	}
+}
+
+// validate checks whether the component suffix scheme is valid.
+func (c componentSpec) validate() error {
+	switch strings.ToLower(c.ComponentSuffix) {
+	case SuffixXYZ, SuffixXYZTensor, SuffixIndex:
+		return nil
+	}
+	return fmt.Errorf("%w: %q: %q",
+		common.ErrBadFieldValue, "component_suffix", c.ComponentSuffix)
+}
+
+// suffix returns the suffix of the i-th component of an n-tuple.
+func (c componentSpec) suffix(n, i int) string {
+	var suffixes map[int][]string
+	switch strings.ToLower(c.ComponentSuffix) {
+	case SuffixXYZ:
+		suffixes = suffixesXYZ
+	case SuffixXYZTensor:
+		suffixes = suffixesXYZTensor
+	}
+	if s, found := suffixes[n]; found {
+		return s[i]
+	}
+	return strconv.Itoa(i)
+}
+
+// names returns the component names of a field named name with
+// the shape s. Nested tuples are named level by level, the components of
+// the innermost tuples are named using the suffix scheme, while the
+// components of outer tuples are named by their index, or by sub, if
+// the number of names in sub matches the number of outer tuple components.
+// Scalar fields retain their name.
+func (c componentSpec) names(name string, sub []string, s datenc.Shape) []string {
+	return c.appendNames(nil, name, sub, s)
+}
+
+func (c componentSpec) appendNames(names []string, name string, sub []string, s datenc.Shape) []string {
+	if s == nil {
+		return append(names, name)
+	}
+	leaf := true
+	for _, e := range s {
+		leaf = leaf && e == nil
+	}
+	for i, e := range s {
+		var sfx string
+		switch {
+		case len(sub) == len(s):
+			sfx = sub[i]
+		case leaf:
+			sfx = c.suffix(len(s), i)
+		default:
+			sfx = strconv.Itoa(i)
+		}
+		names = c.appendNames(names, name+c.ComponentSeparator+sfx, nil, e)
+	}
+	return names
+}
+
+// expandHeader expands the header fields of a record, with the field
+// shapes shapes, into component names.
+// A header field can optionally list the names of the outermost tuple
+// components in parentheses, e.g., 'forces(pressure viscous)'.
+// If the header already names each scalar component, it is returned as is.
+func (c componentSpec) expandHeader(header []string, shapes []datenc.Shape) ([]string, error) {
+	n := 0
+	for _, s := range shapes {
+		n += s.Len()
+	}
+	if len(header) == n {
+		return header, nil
+	}
+	if len(header) != len(shapes) {
+		return nil, fmt.Errorf("%w: %v != %v", ErrDatHeader, len(header), len(shapes))
+	}
+	names := make([]string, 0, n)
+	for i, h := range header {
+		name, sub := h, []string(nil)
+		if l, r := strings.IndexByte(h, '('), strings.LastIndexByte(h, ')'); l != -1 && r > l {
+			name, sub = h[:l], strings.Fields(h[l+1:r])
+		}
+		names = c.appendNames(names, name, sub, shapes[i])
+	}
+	return names, nil
+}
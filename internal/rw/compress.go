@@ -0,0 +1,133 @@
+package rw
+
+import (
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ulikunitz/xz"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported single-file compression extensions.
+const (
+	GzipExt  string = ".gz"
+	Bzip2Ext string = ".bz2"
+	XzExt    string = ".xz"
+)
+
+var (
+	ErrCompressUnsupported = errors.New("bzip2 compressed output is not supported")
+)
+
+// CompressionExt returns the compression extension of the file name,
+// or an empty string if the file is not compressed.
+func CompressionExt(name string) string {
+	switch ext := filepath.Ext(name); ext {
+	case GzipExt, Bzip2Ext, XzExt:
+		return ext
+	}
+	return ""
+}
+
+// readCloser is an io.ReadCloser which closes several closers.
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (rc *readCloser) Close() error {
+	var errs []error
+	for _, c := range rc.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// decompress wraps rc with a decompressing reader, if the file is
+// compressed, based on the file name extension. The file name is taken
+// from the file info of rc, if available, and name otherwise.
+// If an error occurs, rc is closed.
+func decompress(rc io.ReadCloser, name string) (io.ReadCloser, error) {
+	if f, ok := rc.(interface{ Stat() (fs.FileInfo, error) }); ok {
+		if info, err := f.Stat(); err == nil && info.Name() != "" {
+			name = info.Name()
+		}
+	}
+	switch CompressionExt(name) {
+	case GzipExt:
+		r, err := gzip.NewReader(rc)
+		if err != nil {
+			rc.Close()
+			return nil, err
+		}
+		return &readCloser{Reader: r, closers: []io.Closer{r, rc}}, nil
+	case Bzip2Ext:
+		return &readCloser{Reader: bzip2.NewReader(rc), closers: []io.Closer{rc}}, nil
+	case XzExt:
+		r, err := xz.NewReader(rc)
+		if err != nil {
+			rc.Close()
+			return nil, err
+		}
+		return &readCloser{Reader: r, closers: []io.Closer{rc}}, nil
+	}
+	return rc, nil
+}
+
+// writeCloser is an io.WriteCloser which closes the compressing writer
+// before the underlying file.
+type writeCloser struct {
+	io.WriteCloser
+	f io.Closer
+}
+
+func (wc *writeCloser) Close() error {
+	return errors.Join(wc.WriteCloser.Close(), wc.f.Close())
+}
+
+// checkOutput checks whether the output file of the writer config spec,
+// if it has one, can be written, i.e., whether its compression is supported.
+func checkOutput(spec *yaml.Node) error {
+	if spec.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(spec.Content); i += 2 {
+		if k, v := spec.Content[i], spec.Content[i+1]; k.Value == "file" &&
+			v.Kind == yaml.ScalarNode && CompressionExt(v.Value) == Bzip2Ext {
+			return fmt.Errorf("%w: %q", ErrCompressUnsupported, v.Value)
+		}
+	}
+	return nil
+}
+
+// create creates the named file, which is compressed if the file name
+// has a compression extension.
+func create(name string) (io.WriteCloser, error) {
+	ext := CompressionExt(name)
+	if ext == Bzip2Ext {
+		return nil, ErrCompressUnsupported
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	var w io.WriteCloser
+	switch ext {
+	case GzipExt:
+		w = gzip.NewWriter(f)
+	case XzExt:
+		if w, err = xz.NewWriter(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+	default:
+		return f, nil
+	}
+	return &writeCloser{WriteCloser: w, f: f}, nil
+}
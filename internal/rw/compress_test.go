@@ -0,0 +1,187 @@
+package rw
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type compressTest struct {
+	Name   string
+	Config string
+	Output dataframe.DataFrame
+	Error  error
+}
+
+var compressReadTests = []compressTest{
+	{
+		Name: "good-csv-gz",
+		Config: `
+type: csv
+type_spec:
+  file: testdata/data.csv.gz
+`,
+		Output: expected,
+		Error:  nil,
+	},
+	{
+		Name: "good-csv-bz2",
+		Config: `
+type: csv
+type_spec:
+  file: testdata/data.csv.bz2
+`,
+		Output: expected,
+		Error:  nil,
+	},
+	{
+		Name: "good-csv-xz",
+		Config: `
+type: csv
+type_spec:
+  file: testdata/data.csv.xz
+`,
+		Output: expected,
+		Error:  nil,
+	},
+	{
+		Name: "good-dat-xz",
+		Config: `
+type: dat
+fields: [x, y]
+type_spec:
+  file: testdata/data.dat.xz
+`,
+		Output: expected,
+		Error:  nil,
+	},
+	{
+		Name: "good-time-series-gz",
+		Config: `
+type: time-series
+type_spec:
+  directory: testdata/foam_series.good_compressed
+  file: data.csv.gz
+  format_spec:
+    type: csv
+`,
+		Output: expectedSeries,
+		Error:  nil,
+	},
+}
+
+func TestCompressRead(t *testing.T) {
+	for _, tt := range compressReadTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var config Config
+			err := yaml.Unmarshal([]byte(tt.Config), &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+			out, err := Read(&config)
+
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error != nil {
+				assert.Nil(out)
+			} else {
+				assert.Equal(tt.Output, *out)
+			}
+		})
+	}
+}
+
+func TestCompressWrite(t *testing.T) {
+	dir := t.TempDir()
+	for _, tt := range []struct {
+		File  string
+		Error error
+	}{
+		{"data.csv.gz", nil},
+		{"data.csv.xz", nil},
+		{"data.csv.bz2", ErrCompressUnsupported},
+	} {
+		t.Run(tt.File, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rw := defaultCsv()
+			rw.File = filepath.Join(dir, tt.File)
+			err := rw.Write(&expected)
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error != nil {
+				return
+			}
+			out, err := rw.Read()
+			assert.Nil(err, "unexpected csv.Read() error")
+			assert.Equal(expected, *out)
+		})
+	}
+}
+
+func TestSetExt(t *testing.T) {
+	for _, tt := range []struct {
+		Path   string
+		Ext    string
+		Output string
+	}{
+		{"data", ".csv", "data.csv"},
+		{"data.csv", ".csv", "data.csv"},
+		{"data.txt", ".csv", "data.csv"},
+		{"data.gz", ".csv", "data.csv.gz"},
+		{"data.csv.gz", ".csv", "data.csv.gz"},
+		{"data.txt.xz", ".csv", "data.csv.xz"},
+	} {
+		t.Run(tt.Path, func(t *testing.T) {
+			assert.Equal(t, tt.Output, SetExt(tt.Path, tt.Ext))
+		})
+	}
+}
+
+func TestCompressionExt(t *testing.T) {
+	assert.Equal(t, GzipExt, CompressionExt("data.csv.gz"))
+	assert.Equal(t, Bzip2Ext, CompressionExt("data.csv.bz2"))
+	assert.Equal(t, XzExt, CompressionExt("data.dat.xz"))
+	assert.Equal(t, "", CompressionExt("data.csv"))
+}
+
+func TestCompressWriteUnsupported(t *testing.T) {
+	dir := t.TempDir()
+	for _, tt := range []struct {
+		Name    string
+		Config  string
+		SplitBy []string
+	}{
+		{
+			Name: "csv",
+			Config: `
+file: ` + filepath.Join(dir, "data.csv.bz2"),
+		},
+		{
+			Name: "split",
+			Config: `
+file: ` + filepath.Join(dir, "data_{x}.json.bz2"),
+			SplitBy: []string{"x"},
+		},
+	} {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var spec yaml.Node
+			err := yaml.Unmarshal([]byte(tt.Config), &spec)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+			typ := "csv"
+			if len(tt.SplitBy) > 0 {
+				typ = "json"
+			}
+			config := Config{Type: typ, SplitBy: tt.SplitBy, TypeSpec: *spec.Content[0]}
+			err = Write(&expected, []Config{config})
+			assert.ErrorIs(err, ErrCompressUnsupported)
+
+			files, err := filepath.Glob(filepath.Join(dir, "*"))
+			assert.Nil(err)
+			assert.Empty(files, "nothing should be written")
+		})
+	}
+}
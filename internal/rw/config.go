@@ -2,6 +2,7 @@ package rw
 
 import (
 	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -19,25 +20,60 @@ type Config struct {
 	Fields []string `yaml:"fields"`
 	// TypeSpec is the input type specification.
 	TypeSpec yaml.Node `yaml:"type_spec"`
+	// Schema maps field names to field schemas, which define the field
+	// types and missing value tokens. If defined, the read fields are
+	// converted to the types defined by the schema.
+	Schema map[string]FieldSchema `yaml:"schema"`
+	// Strict determines whether values which cannot be converted to
+	// the type defined by the schema result in an error, instead of NaN.
+	Strict bool `yaml:"strict"`
+	// MaxRows is the maximum number of rows written on output. If the data
+	// holds more rows, they are decimated, as defined by Decimation,
+	// before being written. Unlimited if 0.
+	MaxRows int `yaml:"max_rows"`
+	// Decimation defines how the rows are decimated on output.
+	Decimation decimation `yaml:"decimation"`
+	// SplitBy is a list of field names by which the data is split on
+	// output. A Writer is executed for each distinct combination of
+	// the field values, with each '{field}' placeholder in the TypeSpec
+	// replaced by the value of the field, e.g., 'probe_{probe}.csv'.
+	SplitBy []string `yaml:"split_by"`
 }
 
 func (c *Config) IsEmpty() bool {
 	return c.Type == "" &&
 		c.Fields == nil &&
-		c.TypeSpec.IsZero()
+		c.TypeSpec.IsZero() &&
+		c.Schema == nil &&
+		!c.Strict
+}
+
+// file returns a description of the file named by the 'file' key of
+// the TypeSpec, if defined, for use in error messages.
+func (c *Config) file() string {
+	var spec struct {
+		File string `yaml:"file"`
+	}
+	if err := c.TypeSpec.Decode(&spec); err != nil || spec.File == "" {
+		return ""
+	}
+	return fmt.Sprintf("file %q, ", spec.File)
 }
 
 // SetExt sets the file name extension of path to ext and
-// returns the new path.
+// returns the new path. A compression extension, e.g., '.gz',
+// is kept, i.e., the extension preceding it is set instead.
 func SetExt(path, ext string) string {
+	cext := CompressionExt(path)
+	path = strings.TrimSuffix(path, cext)
 	e := filepath.Ext(path)
 	if e == ext {
-		return path
+		return path + cext
 	}
 	if len(e) == 0 {
-		return path + ext
+		return path + ext + cext
 	}
-	return strings.TrimSuffix(path, e) + ext
+	return strings.TrimSuffix(path, e) + ext + cext
 }
 
 // OutDir is a function which takes a file path and, if necessary, recursively
@@ -1,6 +1,7 @@
 package rw
 
 import (
+	stdcsv "encoding/csv"
 	"fmt"
 	"io"
 	"os"
@@ -29,6 +30,14 @@ type csv struct {
 	Delimiter string `yaml:"delimiter"`
 	// Comment is the character used for denoting CSV comments.
 	Comment string `yaml:"comment"`
+	// Datetime maps field names to datetime specifications, used to format
+	// fields holding seconds since an epoch as datetime values on output.
+	Datetime map[string]*datetime `yaml:"datetime"`
+
+	numberSpec `yaml:",inline"`
+
+	// raw determines whether all fields are read as unparsed strings.
+	raw bool
 }
 
 func defaultCsv() *csv {
@@ -36,6 +45,8 @@ func defaultCsv() *csv {
 		Header:    true,
 		Delimiter: string(CSVDelimiter),
 		Comment:   string(CSVComment),
+
+		numberSpec: defaultNumberSpec(),
 	}
 }
 
@@ -44,6 +55,21 @@ func NewCsv(n *yaml.Node) (*csv, error) {
 	if err := n.Decode(rw); err != nil {
 		return nil, fmt.Errorf("csv: %w", err)
 	}
+	if err := rw.numberSpec.validate(); err != nil {
+		return nil, fmt.Errorf("csv: %w", err)
+	}
+	for name, d := range rw.Datetime {
+		if d == nil {
+			d = &datetime{}
+			rw.Datetime[name] = d
+		}
+		if d.first() {
+			return nil, fmt.Errorf("csv: %w: %q: %q", common.ErrBadFieldValue, "epoch", d.Epoch)
+		}
+		if err := d.init(); err != nil {
+			return nil, fmt.Errorf("csv: %w", err)
+		}
+	}
 	return rw, nil
 }
 
@@ -65,27 +91,37 @@ func (rw *csv) ReadFromFn(fn ReaderFunc) (*dataframe.DataFrame, error) {
 	if err != nil {
 		return nil, fmt.Errorf("csv: %w", err)
 	}
+	if rc, err = decompress(rc, rw.File); err != nil {
+		return nil, fmt.Errorf("csv: %w", err)
+	}
 	defer rc.Close()
 	return rw.read(rc)
 }
 
+func (rw *csv) setRaw() { rw.raw = true }
+
 func (rw *csv) read(in io.Reader) (*dataframe.DataFrame, error) {
-	df := dataframe.ReadCSV(
-		in,
+	r := stdcsv.NewReader(in)
+	r.Comma = DecodeRuneOrDefault(rw.Delimiter, CSVDelimiter)
+	r.Comment = DecodeRuneOrDefault(rw.Comment, CSVComment)
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("csv: %w", err)
+	}
+	opts := []dataframe.LoadOption{
 		dataframe.HasHeader(rw.Header),
-		dataframe.WithDelimiter(DecodeRuneOrDefault(rw.Delimiter, CSVDelimiter)),
-		dataframe.WithComments(DecodeRuneOrDefault(rw.Comment, CSVComment)),
 		dataframe.DefaultType(series.Float),
-	)
+	}
+	df := rw.numberSpec.loadRecords(records, rw.Header, rw.raw, opts)
 	if df.Error() != nil {
 		return nil, fmt.Errorf("csv: %w", df.Error())
 	}
 	return &df, nil
 }
 
-// WriteCSV writes df to a CSV file, using options from the config.
-// FIXME: LaTeX has an upper size limit for CSV files that it can handle
-// so the output should be decimated down to this size if it's too large.
+// Write writes df to a CSV file, using options from the config.
+// LaTeX has an upper size limit for CSV files that it can handle,
+// so large outputs should be decimated, see Config.MaxRows.
 func (rw *csv) Write(df *dataframe.DataFrame) error {
 	if rw.File == "" {
 		return fmt.Errorf("csv: %w: %v", common.ErrUnsetField, "file")
@@ -98,11 +134,12 @@ func (rw *csv) Write(df *dataframe.DataFrame) error {
 	if rw.EnforceExtension {
 		path = SetExt(path, CSVExt)
 	}
-	f, err := os.Create(path)
+	f, err := create(path)
 	if err != nil {
 		return fmt.Errorf("csv: %w", err)
 	}
-	if err := df.WriteCSV(f, dataframe.WriteHeader(rw.Header)); err != nil {
+	if err := rw.write(f, df); err != nil {
+		f.Close()
 		return fmt.Errorf("csv: %w", err)
 	}
 	if err := f.Close(); err != nil {
@@ -110,3 +147,20 @@ func (rw *csv) Write(df *dataframe.DataFrame) error {
 	}
 	return nil
 }
+
+func (rw *csv) write(out io.Writer, df *dataframe.DataFrame) error {
+	df, err := formatDatetimes(df, rw.Datetime, rw.numberSpec)
+	if err != nil {
+		return err
+	}
+	if df.Error() != nil {
+		return df.Error()
+	}
+	records := rw.numberSpec.formatRecords(df, formatFloat)
+	if !rw.Header {
+		records = records[1:]
+	}
+	w := stdcsv.NewWriter(out)
+	w.Comma = DecodeRuneOrDefault(rw.Delimiter, CSVDelimiter)
+	return w.WriteAll(records)
+}
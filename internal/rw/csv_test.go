@@ -1,7 +1,9 @@
 package rw
 
 import (
+	"bytes"
 	"io"
+	"math"
 	"strings"
 	"testing"
 
@@ -88,6 +90,48 @@ comment: "§"
 		),
 		Error: nil,
 	},
+	{
+		Name: "good-decimal-comma",
+		Config: `
+delimiter: ";"
+decimal_separator: ","
+thousands_separator: "."
+`,
+		Input: "x;y;s\n1,25;3,5;a,b\n1.000,5;-2;c\n",
+		Output: dataframe.New(
+			series.New([]float64{1.25, 1000.5}, series.Float, "x"),
+			series.New([]float64{3.5, -2}, series.Float, "y"),
+			series.New([]string{"a,b", "c"}, series.String, "s"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-decimal-comma-strings",
+		Config: `
+delimiter: ";"
+decimal_separator: ","
+thousands_separator: "."
+`,
+		Input: "date;version;x\n12.05.2023;1.5;1.234,5\n13.05.2023;1.5;2\n",
+		Output: dataframe.New(
+			series.New([]string{"12.05.2023", "13.05.2023"}, series.String, "date"),
+			series.New([]string{"1.5", "1.5"}, series.String, "version"),
+			series.New([]float64{1234.5, 2}, series.Float, "x"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-thousands-separator",
+		Config: `
+thousands_separator: " "
+`,
+		Input: "x,y\n1 000,1\n12 345 678,2",
+		Output: dataframe.New(
+			series.New([]int{1000, 12345678}, series.Int, "x"),
+			series.New([]int{1, 2}, series.Int, "y"),
+		),
+		Error: nil,
+	},
 }
 
 func TestCsvRead(t *testing.T) {
@@ -114,3 +158,77 @@ func TestCsvRead(t *testing.T) {
 		})
 	}
 }
+
+type csvWriteTest struct {
+	Name   string
+	Config string
+	Input  dataframe.DataFrame
+	Output string
+}
+
+var csvWriteInput = dataframe.New(
+	series.New([]float64{1.25, -1234.5}, series.Float, "x"),
+	series.New([]int{1000, 2}, series.Int, "n"),
+	series.New([]string{"a", "b"}, series.String, "s"),
+)
+
+var csvWriteTests = []csvWriteTest{
+	{
+		Name:   "good-default",
+		Config: ``,
+		Input:  csvWriteInput,
+		Output: "x,n,s\n1.250000,1000,a\n-1234.500000,2,b\n",
+	},
+	{
+		Name: "good-delimiter-no-header",
+		Config: `
+header: false
+delimiter: "\t"
+`,
+		Input:  csvWriteInput,
+		Output: "1.250000\t1000\ta\n-1234.500000\t2\tb\n",
+	},
+	{
+		Name: "good-decimal-comma",
+		Config: `
+delimiter: ";"
+decimal_separator: ","
+thousands_separator: "."
+`,
+		Input:  csvWriteInput,
+		Output: "x;n;s\n1,250000;1.000;a\n-1.234,500000;2;b\n",
+	},
+	{
+		Name: "good-float-format",
+		Config: `
+float_format: "%.2e"
+precision:
+  x: 1
+nan: ""
+`,
+		Input: dataframe.New(
+			series.New([]float64{1.25, math.NaN()}, series.Float, "x"),
+			series.New([]float64{-1234.5, 0.001}, series.Float, "y"),
+		),
+		Output: "x,y\n1.2e+00,-1.23e+03\n,1.00e-03\n",
+	},
+}
+
+func TestCsvWrite(t *testing.T) {
+	for _, tt := range csvWriteTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var config yaml.Node
+			err := yaml.Unmarshal([]byte(tt.Config), &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+			rw, err := NewCsv(&config)
+			assert.Nil(err, "unexpected NewCsv() error")
+
+			var b bytes.Buffer
+			err = rw.write(&b, &tt.Input)
+			assert.Nil(err)
+			assert.Equal(tt.Output, b.String())
+		})
+	}
+}
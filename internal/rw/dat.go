@@ -1,10 +1,12 @@
 package rw
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 
+	"github.com/Milover/post/internal/common"
 	datenc "github.com/Milover/post/internal/encoding/dat"
 	"github.com/go-gota/gota/dataframe"
 	"github.com/go-gota/gota/series"
@@ -16,13 +18,36 @@ const (
 	DATExt string = ".dat"
 )
 
+var (
+	ErrDatHeader = errors.New("dat: header and record field counts differ")
+)
+
 type dat struct {
 	// File is the file path from which data is read or written to.
 	File string `yaml:"file"`
+	// EnforceExtension determines whether a file name extension will be
+	// enforced on the output file name.
+	EnforceExtension bool `yaml:"enforce_extension"`
+	// Header determines whether the field names are read from the header,
+	// i.e., the last comment line preceding the data, as written by
+	// OpenFOAM functionObjects.
+	// Header fields of tuple valued fields, e.g., vectors, are expanded
+	// into component fields, e.g., 'U' into 'U_x', 'U_y' and 'U_z'.
+	// On output, it determines whether a header line is written.
+	Header bool `yaml:"header"`
+
+	componentSpec `yaml:",inline"`
+	numberSpec    `yaml:",inline"`
+
+	// raw determines whether all fields are read as unparsed strings.
+	raw bool
 }
 
 func defaultDat() *dat {
-	return &dat{}
+	return &dat{
+		componentSpec: defaultComponentSpec(),
+		numberSpec:    defaultNumberSpec(),
+	}
 }
 
 func NewDat(n *yaml.Node) (*dat, error) {
@@ -30,6 +55,12 @@ func NewDat(n *yaml.Node) (*dat, error) {
 	if err := n.Decode(rw); err != nil {
 		return nil, fmt.Errorf("dat: %w", err)
 	}
+	if err := rw.componentSpec.validate(); err != nil {
+		return nil, fmt.Errorf("dat: %w", err)
+	}
+	if err := rw.numberSpec.validate(); err != nil {
+		return nil, fmt.Errorf("dat: %w", err)
+	}
 	return rw, nil
 }
 
@@ -51,23 +82,91 @@ func (rw *dat) ReadFromFn(fn ReaderFunc) (*dataframe.DataFrame, error) {
 	if err != nil {
 		return nil, fmt.Errorf("dat: %w", err)
 	}
+	if rc, err = decompress(rc, rw.File); err != nil {
+		return nil, fmt.Errorf("dat: %w", err)
+	}
 	defer rc.Close()
 	return rw.read(rc)
 }
 
+func (rw *dat) setRaw() { rw.raw = true }
+
 func (rw *dat) read(in io.Reader) (*dataframe.DataFrame, error) {
 	r := datenc.NewReader(in)
+	var header []string
+	var err error
+	if rw.Header {
+		if header, err = r.ReadHeader(); err != nil {
+			return nil, fmt.Errorf("dat: %w", err)
+		}
+	}
 	records, err := r.ReadAll()
 	if err != nil {
 		return nil, fmt.Errorf("dat: %w", err)
 	}
-	df := dataframe.LoadRecords(
-		records,
-		dataframe.HasHeader(false),
+	if rw.Header {
+		if len(records) > 0 {
+			if header, err = rw.expandHeader(header, r.Shapes()); err != nil {
+				return nil, err
+			}
+		}
+		records = append([][]string{header}, records...)
+	}
+	opts := []dataframe.LoadOption{
+		dataframe.HasHeader(rw.Header),
 		dataframe.DefaultType(series.Float),
-	)
+	}
+	df := rw.numberSpec.loadRecords(records, rw.Header, rw.raw, opts)
 	if df.Error() != nil {
 		return nil, fmt.Errorf("dat: %w", df.Error())
 	}
 	return &df, nil
 }
+
+// Write writes df to a DAT file, as column-aligned, whitespace-separated
+// values, using options from the config.
+// By default, values are written such that they are read back unchanged,
+// i.e., floats are formatted exactly and always as floats.
+func (rw *dat) Write(df *dataframe.DataFrame) error {
+	if rw.File == "" {
+		return fmt.Errorf("dat: %w: %v", common.ErrUnsetField, "file")
+	}
+	if err := OutDir(rw.File); err != nil {
+		return fmt.Errorf("dat: %w", err)
+	}
+	path := rw.File
+	if rw.EnforceExtension {
+		path = SetExt(path, DATExt)
+	}
+	f, err := create(path)
+	if err != nil {
+		return fmt.Errorf("dat: %w", err)
+	}
+	if err := rw.write(f, df); err != nil {
+		f.Close()
+		return fmt.Errorf("dat: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("dat: %w", err)
+	}
+	return nil
+}
+
+func (rw *dat) write(out io.Writer, df *dataframe.DataFrame) error {
+	if df.Error() != nil {
+		return df.Error()
+	}
+	records := rw.numberSpec.formatRecords(df, formatFloatExact)[1:]
+	var header []string
+	if rw.Header {
+		header = df.Names()
+	}
+	w := datenc.NewWriter(out)
+	w.Align(header, records)
+	if rw.Header {
+		if err := w.WriteHeader(header); err != nil {
+			return err
+		}
+	}
+	return w.WriteAll(records)
+}
@@ -1,10 +1,13 @@
 package rw
 
 import (
+	"bytes"
 	"io"
+	"math"
 	"strings"
 	"testing"
 
+	datenc "github.com/Milover/post/internal/encoding/dat"
 	"github.com/go-gota/gota/dataframe"
 	"github.com/go-gota/gota/series"
 	"github.com/stretchr/testify/assert"
@@ -33,6 +36,121 @@ var datReadTests = []datTest{
 		),
 		Error: nil,
 	},
+	{
+		Name: "good-header",
+		Config: `
+header: true
+`,
+		Input: "# Probe 0 (0 0 0)\n#\tTime\tx\ty\n0 \t1\t2\n1 \t2\t3\n",
+		Output: dataframe.New(
+			series.New([]int{0, 1}, series.Int, "Time"),
+			series.New([]int{1, 2}, series.Int, "x"),
+			series.New([]int{2, 3}, series.Int, "y"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-header-vector",
+		Config: `
+header: true
+`,
+		Input: "#\tTime\tp\tU\n0\t1\t(0 1 2)\n1\t2\t(3 4 5)\n",
+		Output: dataframe.New(
+			series.New([]int{0, 1}, series.Int, "Time"),
+			series.New([]int{1, 2}, series.Int, "p"),
+			series.New([]int{0, 3}, series.Int, "U_x"),
+			series.New([]int{1, 4}, series.Int, "U_y"),
+			series.New([]int{2, 5}, series.Int, "U_z"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-header-probes",
+		Config: `
+header: true
+`,
+		Input: "# Probe 0 (0 0 0)\n# Probe 1 (1 0 0)\n#\tProbe\t0\t1\n#\tTime\n0\t(0 1 2)\t(3 4 5)\n",
+		Output: dataframe.New(
+			series.New([]int{0}, series.Int, "Time"),
+			series.New([]int{0}, series.Int, "0_x"),
+			series.New([]int{1}, series.Int, "0_y"),
+			series.New([]int{2}, series.Int, "0_z"),
+			series.New([]int{3}, series.Int, "1_x"),
+			series.New([]int{4}, series.Int, "1_y"),
+			series.New([]int{5}, series.Int, "1_z"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-header-tensor-index",
+		Config: `
+header: true
+component_suffix: index
+component_separator: '.'
+`,
+		Input: "# Time T\n0 (0 1 2 3 4 5 6 7 8)\n",
+		Output: dataframe.New(
+			series.New([]int{0}, series.Int, "Time"),
+			series.New([]int{0}, series.Int, "T.0"),
+			series.New([]int{1}, series.Int, "T.1"),
+			series.New([]int{2}, series.Int, "T.2"),
+			series.New([]int{3}, series.Int, "T.3"),
+			series.New([]int{4}, series.Int, "T.4"),
+			series.New([]int{5}, series.Int, "T.5"),
+			series.New([]int{6}, series.Int, "T.6"),
+			series.New([]int{7}, series.Int, "T.7"),
+			series.New([]int{8}, series.Int, "T.8"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-header-forces",
+		Config: `
+header: true
+`,
+		Input: "# Time forces(pressure viscous)\n0 ((0 1 2) (3 4 5))\n",
+		Output: dataframe.New(
+			series.New([]int{0}, series.Int, "Time"),
+			series.New([]int{0}, series.Int, "forces_pressure_x"),
+			series.New([]int{1}, series.Int, "forces_pressure_y"),
+			series.New([]int{2}, series.Int, "forces_pressure_z"),
+			series.New([]int{3}, series.Int, "forces_viscous_x"),
+			series.New([]int{4}, series.Int, "forces_viscous_y"),
+			series.New([]int{5}, series.Int, "forces_viscous_z"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-header-nested",
+		Config: `
+header: true
+`,
+		Input: "# Time F\n0 ((0 1 2) (3 4 5))\n",
+		Output: dataframe.New(
+			series.New([]int{0}, series.Int, "Time"),
+			series.New([]int{0}, series.Int, "F_0_x"),
+			series.New([]int{1}, series.Int, "F_0_y"),
+			series.New([]int{2}, series.Int, "F_0_z"),
+			series.New([]int{3}, series.Int, "F_1_x"),
+			series.New([]int{4}, series.Int, "F_1_y"),
+			series.New([]int{5}, series.Int, "F_1_z"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-header-flat",
+		Config: `
+header: true
+`,
+		Input: "# Time U_x U_y U_z\n0 (0 1 2)\n",
+		Output: dataframe.New(
+			series.New([]int{0}, series.Int, "Time"),
+			series.New([]int{0}, series.Int, "U_x"),
+			series.New([]int{1}, series.Int, "U_y"),
+			series.New([]int{2}, series.Int, "U_z"),
+		),
+		Error: nil,
+	},
 	//	{ // TODO: not sure how to trigger this one
 	//		Name: "bad-dat-read",
 	//		Config: `
@@ -44,6 +162,21 @@ var datReadTests = []datTest{
 	//		Output: dataframe.DataFrame{},
 	//		Error:  nil,
 	//	},
+	{
+		Name: "good-decimal-comma",
+		Config: `
+decimal_separator: ","
+`,
+		Input: "0,5\t1,25\t(0,1 0 0)\n1\t2,5\t(1 1,5 1)\n",
+		Output: dataframe.New(
+			series.Floats([]float64{0.5, 1}),
+			series.Floats([]float64{1.25, 2.5}),
+			series.Floats([]float64{0.1, 1}),
+			series.Floats([]float64{0, 1.5}),
+			series.Ints([]int{0, 1}),
+		),
+		Error: nil,
+	},
 }
 
 func TestDatRead(t *testing.T) {
@@ -70,3 +203,112 @@ func TestDatRead(t *testing.T) {
 		})
 	}
 }
+
+type datWriteTest struct {
+	Name   string
+	Config string
+	Input  dataframe.DataFrame
+	Output string
+}
+
+var datWriteInput = dataframe.New(
+	series.New([]float64{0.1, 2, math.NaN()}, series.Float, "time"),
+	series.New([]int{1, -20, 300}, series.Int, "n"),
+	series.New([]string{"a", "bc", "d"}, series.String, "s"),
+)
+
+var datWriteTests = []datWriteTest{
+	{
+		Name:   "good-default",
+		Config: ``,
+		Input:  datWriteInput,
+		Output: "0.1 1   a\n2.0 -20 bc\nNaN 300 d\n",
+	},
+	{
+		Name: "good-header",
+		Config: `
+header: true
+`,
+		Input:  datWriteInput,
+		Output: "# time n   s\n0.1    1   a\n2.0    -20 bc\nNaN    300 d\n",
+	},
+	{
+		Name: "good-decimal-comma",
+		Config: `
+decimal_separator: ","
+`,
+		Input:  datWriteInput,
+		Output: "0,1 1   a\n2,0 -20 bc\nNaN 300 d\n",
+	},
+}
+
+func TestDatWrite(t *testing.T) {
+	for _, tt := range datWriteTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var config yaml.Node
+			err := yaml.Unmarshal([]byte(tt.Config), &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+			rw, err := NewDat(&config)
+			assert.Nil(err, "unexpected NewDat() error")
+
+			var b bytes.Buffer
+			err = rw.write(&b, &tt.Input)
+			assert.Nil(err)
+			assert.Equal(tt.Output, b.String())
+
+			// round-trip
+			out, err := rw.read(&b)
+			assert.Nil(err)
+			if !rw.Header {
+				assert.Nil(out.SetNames(tt.Input.Names()...))
+			}
+			assert.Equal(tt.Input.Types(), out.Types())
+			assert.Equal(tt.Input.Records(), out.Records())
+		})
+	}
+}
+
+func TestDatWriteExact(t *testing.T) {
+	assert := assert.New(t)
+
+	in := dataframe.New(
+		series.New([]float64{0.1, 1.0 / 3, 1e-300, 12345678.9, -2}, series.Float, "x"),
+	)
+	rw := defaultDat()
+	rw.Header = true
+	var b bytes.Buffer
+	assert.Nil(rw.write(&b, &in))
+	out, err := rw.read(&b)
+	assert.Nil(err)
+	assert.Equal(in.Col("x").Float(), out.Col("x").Float())
+}
+
+func TestComponentNames(t *testing.T) {
+	leaf := func(n int) datenc.Shape { return make(datenc.Shape, n) }
+	for _, tt := range []struct {
+		Name   string
+		Suffix string
+		Shape  datenc.Shape
+		Output []string
+	}{
+		{"xyz-vector", SuffixXYZ, leaf(3), []string{"U_x", "U_y", "U_z"}},
+		{"xyz-2-tuple", SuffixXYZ, leaf(2), []string{"U_0", "U_1"}},
+		{"xyz-tensor", SuffixXYZ, leaf(9), []string{
+			"U_0", "U_1", "U_2", "U_3", "U_4", "U_5", "U_6", "U_7", "U_8"}},
+		{"xyz-tensor-symm", SuffixXYZTensor, leaf(6), []string{
+			"U_xx", "U_xy", "U_xz", "U_yy", "U_yz", "U_zz"}},
+		{"xyz-tensor-full", SuffixXYZTensor, leaf(9), []string{
+			"U_xx", "U_xy", "U_xz", "U_yx", "U_yy", "U_yz", "U_zx", "U_zy", "U_zz"}},
+		{"xyz-tensor-2-tuple", SuffixXYZTensor, leaf(2), []string{"U_0", "U_1"}},
+		{"index-vector", SuffixIndex, leaf(3), []string{"U_0", "U_1", "U_2"}},
+	} {
+		t.Run(tt.Name, func(t *testing.T) {
+			c := defaultComponentSpec()
+			c.ComponentSuffix = tt.Suffix
+			assert.Nil(t, c.validate())
+			assert.Equal(t, tt.Output, c.names("U", nil, tt.Shape))
+		})
+	}
+}
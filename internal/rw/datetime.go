@@ -0,0 +1,229 @@
+package rw
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+)
+
+// Datetime epochs.
+const (
+	EpochUnix  string = "unix"
+	EpochFirst string = "first"
+)
+
+var (
+	ErrStrftime = errors.New("datetime: bad strftime directive")
+)
+
+// datetimeLayouts are the layouts tried when parsing datetime values
+// if no layout is defined.
+var datetimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	time.DateOnly,
+}
+
+// strftimeDirectives maps strftime directives to Go time layout elements.
+var strftimeDirectives = map[byte]string{
+	'Y': "2006",
+	'y': "06",
+	'm': "01",
+	'd': "02",
+	'e': "_2",
+	'j': "002",
+	'H': "15",
+	'I': "03",
+	'M': "04",
+	'S': "05",
+	'f': "000000",
+	'p': "PM",
+	'b': "Jan",
+	'B': "January",
+	'a': "Mon",
+	'A': "Monday",
+	'z': "-0700",
+	'Z': "MST",
+	'F': "2006-01-02",
+	'T': "15:04:05",
+	'%': "%",
+}
+
+// strftime converts a strftime-like pattern into a Go time layout.
+func strftime(pattern string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' {
+			b.WriteByte(pattern[i])
+			continue
+		}
+		if i++; i == len(pattern) {
+			return "", fmt.Errorf("%w: %q", ErrStrftime, "%")
+		}
+		d, found := strftimeDirectives[pattern[i]]
+		if !found {
+			return "", fmt.Errorf("%w: %q", ErrStrftime, pattern[i-1:i+1])
+		}
+		b.WriteString(d)
+	}
+	return b.String(), nil
+}
+
+// datetime is a datetime format specification.
+type datetime struct {
+	// Layout is the datetime layout, either a Go time layout,
+	// e.g., '2006-01-02 15:04:05', or a strftime-like pattern,
+	// e.g., '%Y-%m-%d %H:%M:%S'. If unset, RFC 3339 and similar
+	// layouts are used.
+	Layout string `yaml:"layout"`
+	// Timezone is the IANA time zone name, e.g., 'Europe/Zagreb', used for
+	// values which do not specify one. 'UTC' by default.
+	Timezone string `yaml:"timezone"`
+	// Epoch is the time from which seconds are counted, either 'unix',
+	// 'first', i.e., the first value of the field, or a datetime
+	// value, in the layout or an RFC 3339 like layout. 'unix' by default.
+	Epoch string `yaml:"epoch"`
+
+	layout string         // the Go time layout
+	loc    *time.Location // the time zone
+	epoch  time.Time      // the epoch, unless Epoch is 'first'
+}
+
+// init validates the specification and initializes the layout,
+// time zone and epoch.
+func (d *datetime) init() error {
+	d.layout = d.Layout
+	if strings.Contains(d.Layout, "%") {
+		var err error
+		if d.layout, err = strftime(d.Layout); err != nil {
+			return err
+		}
+	}
+	var err error
+	if d.loc, err = time.LoadLocation(d.Timezone); err != nil {
+		return fmt.Errorf("datetime: %w: %q: %v", common.ErrBadFieldValue, "timezone", err)
+	}
+	switch strings.ToLower(d.Epoch) {
+	case "", EpochUnix:
+		d.epoch = time.Unix(0, 0)
+	case EpochFirst:
+	default:
+		if d.epoch, err = d.parseEpoch(); err != nil {
+			return fmt.Errorf("datetime: %w: %q: %v", common.ErrBadFieldValue, "epoch", err)
+		}
+	}
+	return nil
+}
+
+// parseEpoch parses the epoch, using the layout if it matches,
+// or RFC 3339 and similar layouts otherwise.
+func (d *datetime) parseEpoch() (time.Time, error) {
+	if t, err := d.parse(d.Epoch); err == nil || d.layout == "" {
+		return t, err
+	}
+	return (&datetime{loc: d.loc}).parse(d.Epoch)
+}
+
+// first reports whether seconds are counted from the first value.
+func (d *datetime) first() bool {
+	return strings.ToLower(d.Epoch) == EpochFirst
+}
+
+// parse parses a datetime value.
+func (d *datetime) parse(v string) (time.Time, error) {
+	if d.layout != "" {
+		return time.ParseInLocation(d.layout, v, d.loc)
+	}
+	for _, layout := range datetimeLayouts {
+		if t, err := time.ParseInLocation(layout, v, d.loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown datetime format")
+}
+
+// seconds converts datetime values into seconds since the epoch.
+// Zero values are converted to nil, i.e., missing values.
+func (d *datetime) seconds(ts []time.Time) []interface{} {
+	epoch := d.epoch
+	if d.first() {
+		for _, t := range ts {
+			if !t.IsZero() {
+				epoch = t
+				break
+			}
+		}
+	}
+	// time.Duration overflows roughly 292 years from the epoch,
+	// so seconds are computed from the Unix time instead
+	out := make([]interface{}, len(ts))
+	for i, t := range ts {
+		if !t.IsZero() {
+			out[i] = float64(t.Unix()-epoch.Unix()) +
+				float64(t.Nanosecond()-epoch.Nanosecond())/1e9
+		}
+	}
+	return out
+}
+
+// format formats seconds since the epoch as a datetime value.
+// NaN and infinite values are written using the spellings of n.
+func (d *datetime) format(s float64, n numberSpec) string {
+	switch {
+	case math.IsNaN(s):
+		return n.NaN
+	case math.IsInf(s, 1):
+		return n.Inf
+	case math.IsInf(s, -1):
+		return n.negInf()
+	}
+	sec := math.Floor(s)
+	nsec := math.Round((s - sec) * 1e9)
+	t := time.Unix(d.epoch.Unix()+int64(sec), int64(d.epoch.Nanosecond())+int64(nsec))
+	layout := d.layout
+	if layout == "" {
+		layout = time.RFC3339Nano
+	}
+	return t.In(d.loc).Format(layout)
+}
+
+// formatDatetimes formats the fields of df, holding seconds since an epoch,
+// as datetime values, as defined by specs, and returns the formatted
+// dataframe.DataFrame. Missing values are written using the spellings of n.
+func formatDatetimes(df *dataframe.DataFrame, specs map[string]*datetime, n numberSpec) (*dataframe.DataFrame, error) {
+	if len(specs) == 0 {
+		return df, nil
+	}
+	names := df.Names()
+	ss := make([]series.Series, 0, len(specs))
+	for name, d := range specs {
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf("datetime: %w: %q", common.ErrBadField, name)
+		}
+		col := df.Col(name)
+		if t := col.Type(); t != series.Float && t != series.Int {
+			return nil, fmt.Errorf("datetime: %w: %q: %v", common.ErrBadFieldType, name, t)
+		}
+		vals := make([]string, col.Len())
+		for i, s := range col.Float() {
+			vals[i] = d.format(s, n)
+		}
+		ss = append(ss, series.New(vals, series.String, name))
+	}
+	out := df.Mutate(ss[0])
+	for _, s := range ss[1:] {
+		out = out.Mutate(s)
+	}
+	if out.Error() != nil {
+		return nil, fmt.Errorf("datetime: %w", out.Error())
+	}
+	return &out, nil
+}
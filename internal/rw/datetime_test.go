@@ -0,0 +1,144 @@
+package rw
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestStrftime(t *testing.T) {
+	for _, tt := range []struct {
+		Pattern string
+		Output  string
+		Error   error
+	}{
+		{"%Y-%m-%d %H:%M:%S", "2006-01-02 15:04:05", nil},
+		{"%d.%m.%y %I:%M %p", "02.01.06 03:04 PM", nil},
+		{"%FT%T.%f%z", "2006-01-02T15:04:05.000000-0700", nil},
+		{"%a, %e %b %Y %Z", "Mon, _2 Jan 2006 MST", nil},
+		{"100%% %j", "100% 002", nil},
+		{"%Y-%Q", "", ErrStrftime},
+		{"%Y%", "", ErrStrftime},
+	} {
+		t.Run(tt.Pattern, func(t *testing.T) {
+			out, err := strftime(tt.Pattern)
+			assert.ErrorIs(t, err, tt.Error)
+			assert.Equal(t, tt.Output, out)
+		})
+	}
+}
+
+type datetimeWriteTest struct {
+	Name   string
+	Config string
+	Output string
+	Error  error
+}
+
+var datetimeWriteInput = dataframe.New(
+	series.New([]float64{1710658800, 1710658830.5, math.NaN()}, series.Float, "stamp"),
+	series.New([]int{1, 2, 3}, series.Int, "value"),
+)
+
+var datetimeWriteTests = []datetimeWriteTest{
+	{
+		Name: "good-default",
+		Config: `
+datetime:
+  stamp:
+`,
+		Output: "stamp,value\n2024-03-17T07:00:00Z,1\n2024-03-17T07:00:30.5Z,2\nNaN,3\n",
+		Error:  nil,
+	},
+	{
+		Name: "good-layout-timezone",
+		Config: `
+datetime:
+  stamp:
+    layout: '%d/%m/%Y %H:%M:%S'
+    timezone: Europe/Zagreb
+`,
+		Output: "stamp,value\n17/03/2024 08:00:00,1\n17/03/2024 08:00:30,2\nNaN,3\n",
+		Error:  nil,
+	},
+	{
+		Name: "good-epoch",
+		Config: `
+datetime:
+  value:
+    layout: '15:04:05'
+    epoch: 2024-03-17
+`,
+		Output: "stamp,value\n1710658800.000000,00:00:01\n1710658830.500000,00:00:02\nNaN,00:00:03\n",
+		Error:  nil,
+	},
+	{
+		Name: "good-nan",
+		Config: `
+nan: '-'
+datetime:
+  stamp:
+`,
+		Output: "stamp,value\n2024-03-17T07:00:00Z,1\n2024-03-17T07:00:30.5Z,2\n-,3\n",
+		Error:  nil,
+	},
+	{
+		Name: "bad-field",
+		Config: `
+datetime:
+  time:
+`,
+		Output: "",
+		Error:  common.ErrBadField,
+	},
+}
+
+func TestDatetimeWrite(t *testing.T) {
+	for _, tt := range datetimeWriteTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var config yaml.Node
+			err := yaml.Unmarshal([]byte(tt.Config), &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+			rw, err := NewCsv(&config)
+			assert.Nil(err, "unexpected NewCsv() error")
+
+			var b bytes.Buffer
+			err = rw.write(&b, &datetimeWriteInput)
+			assert.ErrorIs(err, tt.Error)
+			assert.Equal(tt.Output, b.String())
+		})
+	}
+}
+
+func TestDatetimeWriteFirst(t *testing.T) {
+	var config yaml.Node
+	err := yaml.Unmarshal([]byte("datetime: {stamp: {epoch: first}}"), &config)
+	assert.Nil(t, err, "unexpected yaml.Unmarshal() error")
+	_, err = NewCsv(&config)
+	assert.ErrorIs(t, err, common.ErrBadFieldValue)
+}
+
+func TestDatetimeFarFromEpoch(t *testing.T) {
+	assert := assert.New(t)
+
+	var d datetime
+	assert.Nil(d.init(), "unexpected init() error")
+	ts := []time.Time{
+		time.Date(1200, 1, 1, 0, 0, 0, 500000000, time.UTC),
+		time.Date(2800, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	out := d.seconds(ts)
+	assert.Equal([]interface{}{-24298876799.5, 26192246400.0}, out)
+	for i, s := range out {
+		assert.Equal(ts[i].Format(time.RFC3339Nano), d.format(s.(float64), defaultNumberSpec()))
+	}
+}
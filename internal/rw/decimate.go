@@ -0,0 +1,229 @@
+package rw
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+)
+
+// Decimation methods.
+const (
+	// DecimateStride keeps uniformly spaced rows.
+	DecimateStride string = "stride"
+	// DecimateMinMax keeps the rows holding the minimum and maximum
+	// values of each field within uniformly sized buckets.
+	DecimateMinMax string = "minmax"
+	// DecimateLTTB keeps rows using the Largest-Triangle-Three-Buckets
+	// algorithm.
+	DecimateLTTB string = "lttb"
+)
+
+// decimation defines how rows are decimated.
+type decimation struct {
+	// Method is the decimation method, one of 'stride', 'minmax' or 'lttb'.
+	// 'stride' by default.
+	Method string `yaml:"method"`
+	// XField is the independent variable field name used by 'lttb'.
+	// The row index is used if unset.
+	XField string `yaml:"x_field"`
+	// Fields is a list of field names whose shape is preserved by 'minmax'
+	// and 'lttb'. All numeric fields, except XField, by default.
+	Fields []string `yaml:"fields"`
+}
+
+// decimate returns a dataframe.DataFrame holding at most maxRows rows
+// of df, selected using the decimation method. The first and last rows are
+// always kept. df is returned unchanged if it holds at most maxRows rows.
+func decimate(df *dataframe.DataFrame, maxRows int, d *decimation) (*dataframe.DataFrame, error) {
+	if maxRows < 0 {
+		return nil, fmt.Errorf("decimate: %w: %q: %v",
+			common.ErrBadFieldValue, "max_rows", maxRows)
+	}
+	if maxRows == 0 || df.Nrow() <= maxRows {
+		return df, nil
+	}
+	names := df.Names()
+	if d.XField != "" && !slices.Contains(names, d.XField) {
+		return nil, fmt.Errorf("decimate: %w: %q", common.ErrBadField, d.XField)
+	}
+	fields := d.Fields
+	if len(fields) == 0 {
+		for _, name := range names {
+			t := df.Col(name).Type()
+			if name != d.XField && (t == series.Float || t == series.Int) {
+				fields = append(fields, name)
+			}
+		}
+	}
+	ys := make([][]float64, len(fields))
+	for i, name := range fields {
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf("decimate: %w: %q", common.ErrBadField, name)
+		}
+		ys[i] = df.Col(name).Float()
+	}
+
+	var idx []int
+	switch strings.ToLower(d.Method) {
+	case "", DecimateStride:
+		idx = decimateStride(df.Nrow(), maxRows)
+	case DecimateMinMax:
+		idx = decimateMinMax(df.Nrow(), maxRows, ys)
+	case DecimateLTTB:
+		var x []float64
+		if d.XField != "" {
+			x = df.Col(d.XField).Float()
+		}
+		idx = decimateLTTB(df.Nrow(), maxRows, x, ys)
+	default:
+		return nil, fmt.Errorf("decimate: %w: %q: %q",
+			common.ErrBadFieldValue, "method", d.Method)
+	}
+	out := df.Subset(idx)
+	if out.Error() != nil {
+		return nil, fmt.Errorf("decimate: %w", out.Error())
+	}
+	return &out, nil
+}
+
+// decimateStride returns the indices of m uniformly spaced rows
+// out of n rows.
+func decimateStride(n, m int) []int {
+	if m == 1 {
+		return []int{0}
+	}
+	idx := make([]int, m)
+	for i := range idx {
+		idx[i] = int(math.Round(float64(i) * float64(n-1) / float64(m-1)))
+	}
+	return idx
+}
+
+// buckets returns the bounds of k uniformly sized buckets partitioning
+// the rows [start, end).
+func buckets(start, end, k int) []int {
+	b := make([]int, k+1)
+	for i := range b {
+		b[i] = start + int(math.Round(float64(i)*float64(end-start)/float64(k)))
+	}
+	return b
+}
+
+// decimateMinMax returns the indices of at most m out of n rows, such that
+// the first and last rows, and the rows holding the minimum and maximum
+// values of each of ys within each bucket, are kept.
+func decimateMinMax(n, m int, ys [][]float64) []int {
+	k := (m - 2) / max(2*len(ys), 1)
+	if k < 1 || len(ys) == 0 {
+		return decimateStride(n, m)
+	}
+	keep := map[int]bool{0: true, n - 1: true}
+	b := buckets(1, n-1, k)
+	for i := 0; i < k; i++ {
+		for _, y := range ys {
+			lo, hi := -1, -1
+			for j := b[i]; j < b[i+1]; j++ {
+				if math.IsNaN(y[j]) {
+					continue
+				}
+				if lo == -1 || y[j] < y[lo] {
+					lo = j
+				}
+				if hi == -1 || y[j] > y[hi] {
+					hi = j
+				}
+			}
+			if lo == -1 { // all NaN
+				lo, hi = b[i], b[i]
+			}
+			keep[lo], keep[hi] = true, true
+		}
+	}
+	idx := common.MapKeys(keep)
+	slices.Sort(idx)
+	return idx
+}
+
+// decimateLTTB returns the indices of m out of n rows selected using
+// the Largest-Triangle-Three-Buckets algorithm, with x as the independent
+// variable, or the row index if x is nil. The triangle areas of all ys are
+// summed, each normalized by the range of its values.
+func decimateLTTB(n, m int, x []float64, ys [][]float64) []int {
+	if m < 3 || len(ys) == 0 {
+		return decimateStride(n, m)
+	}
+	if x == nil {
+		x = make([]float64, n)
+		for i := range x {
+			x[i] = float64(i)
+		}
+	}
+	scale := make([]float64, len(ys))
+	for k, y := range ys {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, v := range y {
+			if !math.IsNaN(v) {
+				lo, hi = min(lo, v), max(hi, v)
+			}
+		}
+		scale[k] = 1
+		if hi > lo {
+			scale[k] = 1 / (hi - lo)
+		}
+	}
+	// area returns the (doubled, scaled) area of the triangle formed by
+	// the rows a and j and the point (cx, cy).
+	area := func(a, j int, cx float64, cy []float64) float64 {
+		s := 0.0
+		for k, y := range ys {
+			v := math.Abs((x[a]-cx)*(y[j]-y[a])-(x[a]-x[j])*(cy[k]-y[a])) * scale[k]
+			if !math.IsNaN(v) {
+				s += v
+			}
+		}
+		return s
+	}
+
+	idx := make([]int, 0, m)
+	idx = append(idx, 0)
+	b := buckets(1, n-1, m-2)
+	cy := make([]float64, len(ys))
+	a := 0
+	for i := 0; i < m-2; i++ {
+		// the average of the next bucket, or the last row
+		next, end := b[i+1], n
+		if i+2 < len(b) {
+			end = b[i+2]
+		}
+		cx := 0.0
+		for k := range cy {
+			cy[k] = 0
+		}
+		for j := next; j < end; j++ {
+			cx += x[j]
+			for k, y := range ys {
+				cy[k] += y[j]
+			}
+		}
+		cnt := float64(end - next)
+		cx /= cnt
+		for k := range cy {
+			cy[k] /= cnt
+		}
+
+		best, bestArea := b[i], -1.0
+		for j := b[i]; j < b[i+1]; j++ {
+			if s := area(a, j, cx, cy); s > bestArea {
+				best, bestArea = j, s
+			}
+		}
+		idx = append(idx, best)
+		a = best
+	}
+	return append(idx, n-1)
+}
@@ -0,0 +1,164 @@
+package rw
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+// decimateInput is a sine wave with a single spike at row 37.
+var decimateInput = func() dataframe.DataFrame {
+	n := 100
+	x := make([]float64, n)
+	y := make([]float64, n)
+	for i := range x {
+		x[i] = 0.1 * float64(i)
+		y[i] = math.Sin(x[i])
+	}
+	y[37] = 10
+	return dataframe.New(
+		series.New(x, series.Float, "x"),
+		series.New(y, series.Float, "y"),
+		series.New(make([]string, n), series.String, "s"),
+	)
+}()
+
+type decimateTest struct {
+	Name    string
+	MaxRows int
+	Config  string
+	Rows    []int // expected rows, or nil if only checked for count
+	Keep    []int // rows which must be kept
+	Error   error
+}
+
+var decimateTests = []decimateTest{
+	{
+		Name:    "good-unlimited",
+		MaxRows: 0,
+		Config:  ``,
+		Rows:    nil,
+		Keep:    []int{0, 37, 99},
+		Error:   nil,
+	},
+	{
+		Name:    "good-stride",
+		MaxRows: 4,
+		Config:  ``,
+		Rows:    []int{0, 33, 66, 99},
+		Error:   nil,
+	},
+	{
+		Name:    "good-stride-single",
+		MaxRows: 1,
+		Config:  `method: stride`,
+		Rows:    []int{0},
+		Error:   nil,
+	},
+	{
+		Name:    "good-minmax",
+		MaxRows: 10,
+		Config:  `method: minmax`,
+		Keep:    []int{0, 37, 99},
+		Error:   nil,
+	},
+	{
+		Name:    "good-lttb",
+		MaxRows: 10,
+		Config:  `method: lttb`,
+		Keep:    []int{0, 37, 99},
+		Error:   nil,
+	},
+	{
+		Name:    "good-lttb-x-field",
+		MaxRows: 10,
+		Config: `
+method: lttb
+x_field: x
+fields: [y]
+`,
+		Keep:  []int{0, 37, 99},
+		Error: nil,
+	},
+	{
+		Name:    "bad-method",
+		MaxRows: 10,
+		Config:  `method: random`,
+		Error:   common.ErrBadFieldValue,
+	},
+	{
+		Name:    "bad-x-field",
+		MaxRows: 10,
+		Config:  `{method: lttb, x_field: t}`,
+		Error:   common.ErrBadField,
+	},
+	{
+		Name:    "bad-max-rows",
+		MaxRows: -1,
+		Config:  ``,
+		Error:   common.ErrBadFieldValue,
+	},
+}
+
+func TestDecimate(t *testing.T) {
+	for _, tt := range decimateTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var d decimation
+			err := yaml.Unmarshal([]byte(tt.Config), &d)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+
+			out, err := decimate(&decimateInput, tt.MaxRows, &d)
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error != nil {
+				assert.Nil(out)
+				return
+			}
+			if tt.MaxRows > 0 {
+				assert.LessOrEqual(out.Nrow(), tt.MaxRows)
+			}
+			assert.Equal(decimateInput.Names(), out.Names())
+			x := out.Col("x").Float()
+			if tt.Rows != nil {
+				rows := make([]int, len(x))
+				for i, v := range x {
+					rows[i] = int(math.Round(v * 10))
+				}
+				assert.Equal(tt.Rows, rows)
+			}
+			for _, r := range tt.Keep {
+				assert.Contains(x, decimateInput.Elem(r, 0).Float())
+			}
+		})
+	}
+}
+
+func TestWriteMaxRows(t *testing.T) {
+	assert := assert.New(t)
+	defer func() { RAM = nil }()
+
+	var configs []Config
+	err := yaml.Unmarshal([]byte(`
+- type: ram
+  max_rows: 5
+  decimation:
+    method: lttb
+  type_spec:
+    name: decimated
+- type: ram
+  type_spec:
+    name: full
+`), &configs)
+	assert.Nil(err, "unexpected yaml.Unmarshal() error")
+
+	in := decimateInput.Copy()
+	assert.Nil(Write(&in, configs))
+	assert.Equal(5, RAM.s["decimated"].Nrow())
+	assert.Equal(decimateInput, *RAM.s["full"])
+}
@@ -0,0 +1,316 @@
+package rw
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FixedWidthComment rune = '#'
+)
+
+var (
+	ErrFixedWidthColumns  = errors.New("fixed-width: 'widths' and 'columns' are mutually exclusive")
+	ErrFixedWidthOverflow = errors.New("fixed-width: value wider than column")
+)
+
+// fixedWidth reads and writes fixed-width column formatted files, i.e.,
+// tables whose columns occupy fixed character positions on each line,
+// hence values of adjacent columns may touch, e.g., '-1.234E+00-5.678E-01'.
+//
+// Columns are defined either by their widths, or by their start positions.
+type fixedWidth struct {
+	// File is the file path from which data is read or written to.
+	File string `yaml:"file"`
+	// Widths is a list of column widths, in characters.
+	Widths []int `yaml:"widths"`
+	// Columns is a list of (0-based) column start positions, in characters.
+	// The last column extends to the end of the line.
+	Columns []int `yaml:"columns"`
+	// Header determines whether the first (non-comment) line is a header
+	// line holding the field names. If unset, the first line is
+	// a header line if none of its values is numeric, and at least one
+	// column holds only numeric values below it.
+	// A header line is always written unless Header is 'false'.
+	Header *bool `yaml:"header"`
+	// Comment is the character used for denoting comments.
+	Comment string `yaml:"comment"`
+
+	numberSpec `yaml:",inline"`
+
+	// raw determines whether all fields are read as unparsed strings.
+	raw bool
+}
+
+func defaultFixedWidth() *fixedWidth {
+	return &fixedWidth{
+		Comment: string(FixedWidthComment),
+
+		numberSpec: defaultNumberSpec(),
+	}
+}
+
+func NewFixedWidth(n *yaml.Node) (*fixedWidth, error) {
+	rw := defaultFixedWidth()
+	if err := n.Decode(rw); err != nil {
+		return nil, fmt.Errorf("fixed-width: %w", err)
+	}
+	if len(rw.Widths) > 0 && len(rw.Columns) > 0 {
+		return nil, ErrFixedWidthColumns
+	}
+	if err := rw.numberSpec.validate(); err != nil {
+		return nil, fmt.Errorf("fixed-width: %w", err)
+	}
+	for _, w := range rw.Widths {
+		if w < 1 {
+			return nil, fmt.Errorf("fixed-width: %w: %q: %v",
+				common.ErrBadFieldValue, "widths", rw.Widths)
+		}
+	}
+	for i, c := range rw.Columns {
+		if c < 0 || (i > 0 && c <= rw.Columns[i-1]) {
+			return nil, fmt.Errorf("fixed-width: %w: %q: %v",
+				common.ErrBadFieldValue, "columns", rw.Columns)
+		}
+	}
+	return rw, nil
+}
+
+// bounds returns the start and end positions of all columns.
+// An end position of -1 denotes the end of the line.
+func (rw *fixedWidth) bounds() (start, end []int) {
+	if len(rw.Widths) > 0 {
+		pos := 0
+		for _, w := range rw.Widths {
+			start = append(start, pos)
+			pos += w
+			end = append(end, pos)
+		}
+		return start, end
+	}
+	start = rw.Columns
+	end = append(append(end, rw.Columns[1:]...), -1)
+	return start, end
+}
+
+func (rw *fixedWidth) Read() (*dataframe.DataFrame, error) {
+	fn := func(name string) (io.ReadCloser, error) {
+		return os.Open(name)
+	}
+	return rw.ReadFromFn(fn)
+}
+
+func (rw *fixedWidth) ReadFromFn(fn ReaderFunc) (*dataframe.DataFrame, error) {
+	if len(rw.Widths) == 0 && len(rw.Columns) == 0 {
+		return nil, fmt.Errorf("fixed-width: %w: %v", common.ErrUnsetField, "widths")
+	}
+	var rc io.ReadCloser
+	var err error
+	if rw.File == "" { // yolo
+		rc, err = fn("")
+	} else {
+		rc, err = fn(rw.File)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fixed-width: %w", err)
+	}
+	if rc, err = decompress(rc, rw.File); err != nil {
+		return nil, fmt.Errorf("fixed-width: %w", err)
+	}
+	defer rc.Close()
+	return rw.read(rc)
+}
+
+func (rw *fixedWidth) setRaw() { rw.raw = true }
+
+// splitFixed splits a line into column values, with leading and trailing
+// white space removed.
+func splitFixed(line []rune, start, end []int) []string {
+	record := make([]string, len(start))
+	for i := range start {
+		s, e := start[i], end[i]
+		if s >= len(line) {
+			continue
+		}
+		if e < 0 || e > len(line) {
+			e = len(line)
+		}
+		record[i] = strings.TrimSpace(string(line[s:e]))
+	}
+	return record
+}
+
+// isNumber reports whether v is a numeric value, written using
+// the separators and spellings.
+func (rw *fixedWidth) isNumber(v string) bool {
+	_, ok := rw.numberSpec.parse(v)
+	return ok
+}
+
+// isHeader reports whether the first record is a header, i.e., whether
+// none of its values is numeric, and at least one column holds only
+// numeric, or empty, values in the remaining records. Hence, a headerless
+// file whose first record holds only strings, e.g., a file of labels,
+// is not detected as having a header.
+func (rw *fixedWidth) isHeader(records [][]string) bool {
+	if len(records) == 0 {
+		return false
+	}
+	named := false
+	for _, v := range records[0] {
+		if rw.isNumber(v) {
+			return false
+		}
+		named = named || v != ""
+	}
+	if !named {
+		return false
+	}
+	for j := range records[0] {
+		numeric := true
+		for _, r := range records[1:] {
+			if r[j] != "" && !rw.isNumber(r[j]) {
+				numeric = false
+				break
+			}
+		}
+		if numeric {
+			return true
+		}
+	}
+	return false
+}
+
+func (rw *fixedWidth) read(in io.Reader) (*dataframe.DataFrame, error) {
+	comment := DecodeRuneOrDefault(rw.Comment, FixedWidthComment)
+	start, end := rw.bounds()
+	var records [][]string
+	s := bufio.NewScanner(in)
+	s.Buffer(nil, 64*1024*1024) // allow wide tables
+	for s.Scan() {
+		line := s.Text()
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) == 0 {
+			continue
+		}
+		if r, _ := utf8.DecodeRuneInString(trimmed); r == comment {
+			continue
+		}
+		records = append(records, splitFixed([]rune(line), start, end))
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("fixed-width: %w", err)
+	}
+	header := rw.isHeader(records)
+	if rw.Header != nil {
+		header = *rw.Header
+	}
+	opts := []dataframe.LoadOption{
+		dataframe.HasHeader(header),
+		dataframe.DefaultType(series.Float),
+	}
+	df := rw.numberSpec.loadRecords(records, header, rw.raw, opts)
+	if df.Error() != nil {
+		return nil, fmt.Errorf("fixed-width: %w", df.Error())
+	}
+	return &df, nil
+}
+
+// Write writes df to a fixed-width column formatted file, with values
+// right-aligned within columns. If neither widths nor column start
+// positions are defined, each column is as wide as its widest value,
+// and columns are separated by a single space.
+func (rw *fixedWidth) Write(df *dataframe.DataFrame) error {
+	if rw.File == "" {
+		return fmt.Errorf("fixed-width: %w: %v", common.ErrUnsetField, "file")
+	}
+	if err := OutDir(rw.File); err != nil {
+		return fmt.Errorf("fixed-width: %w", err)
+	}
+	f, err := create(rw.File)
+	if err != nil {
+		return fmt.Errorf("fixed-width: %w", err)
+	}
+	if err := rw.write(f, df); err != nil {
+		f.Close()
+		return fmt.Errorf("fixed-width: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("fixed-width: %w", err)
+	}
+	return nil
+}
+
+// widths returns the widths of the columns of records.
+func (rw *fixedWidth) widths(records [][]string) ([]int, error) {
+	n := len(records[0])
+	if len(rw.Widths) == 0 && len(rw.Columns) == 0 {
+		widths := make([]int, n)
+		for _, r := range records {
+			for j, v := range r {
+				widths[j] = max(widths[j], utf8.RuneCountInString(v))
+			}
+		}
+		for j := range widths[1:] {
+			widths[j+1]++ // separate columns
+		}
+		return widths, nil
+	}
+	if l := max(len(rw.Widths), len(rw.Columns)); l != n {
+		return nil, fmt.Errorf("%w: got %v columns, expected %v",
+			common.ErrBadFieldValue, l, n)
+	}
+	if len(rw.Widths) > 0 {
+		return rw.Widths, nil
+	}
+	widths := make([]int, n)
+	for j := range widths[:n-1] {
+		widths[j] = rw.Columns[j+1] - rw.Columns[j]
+	}
+	for _, r := range records {
+		widths[n-1] = max(widths[n-1], utf8.RuneCountInString(r[n-1]))
+	}
+	widths[0] += rw.Columns[0]
+	return widths, nil
+}
+
+func (rw *fixedWidth) write(out io.Writer, df *dataframe.DataFrame) error {
+	if df.Error() != nil {
+		return df.Error()
+	}
+	records := rw.numberSpec.formatRecords(df, formatFloat)
+	if rw.Header != nil && !*rw.Header {
+		records = records[1:]
+	}
+	if len(records) == 0 || len(records[0]) == 0 {
+		return nil
+	}
+	widths, err := rw.widths(records)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(out)
+	for i, r := range records {
+		for j, v := range r {
+			pad := widths[j] - utf8.RuneCountInString(v)
+			if pad < 0 {
+				return fmt.Errorf("%w: line %v, column %v: %q",
+					ErrFixedWidthOverflow, i+1, j, v)
+			}
+			w.WriteString(strings.Repeat(" ", pad))
+			w.WriteString(v)
+		}
+		w.WriteByte('\n')
+	}
+	return w.Flush()
+}
@@ -0,0 +1,267 @@
+package rw
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type fixedWidthTest struct {
+	Name   string
+	Config string
+	Input  string
+	Output dataframe.DataFrame
+	Error  error
+}
+
+var fixedWidthReadTests = []fixedWidthTest{
+	{
+		Name: "good-widths",
+		Config: `
+widths: [10, 10, 3]
+`,
+		Input: "# comment\n-1.234E+00-5.678E-01  1\n\n 1.000E+00 2.500E+00 12\n",
+		Output: dataframe.New(
+			series.Floats([]float64{-1.234, 1}),
+			series.Floats([]float64{-0.5678, 2.5}),
+			series.Ints([]int{1, 12}),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-columns-detect-header",
+		Config: `
+columns: [0, 6, 12]
+`,
+		Input: "time  p     name\n0.1   1.5   a\n0.2   2.5   bb bb\n",
+		Output: dataframe.New(
+			series.New([]float64{0.1, 0.2}, series.Float, "time"),
+			series.New([]float64{1.5, 2.5}, series.Float, "p"),
+			series.New([]string{"a", "bb bb"}, series.String, "name"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-header",
+		Config: `
+widths: [2, 2]
+header: true
+`,
+		Input: " 1 2\n 3 4\n",
+		Output: dataframe.New(
+			series.New([]int{3}, series.Int, "1"),
+			series.New([]int{4}, series.Int, "2"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-no-header",
+		Config: `
+widths: [1, 1]
+header: false
+`,
+		Input: "ab\n12\n",
+		Output: dataframe.New(
+			series.Strings([]string{"a", "1"}),
+			series.Strings([]string{"b", "2"}),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-detect-no-header-string",
+		Config: `
+columns: [0, 6]
+`,
+		Input: "a     1.5\nbb    2.5\n",
+		Output: dataframe.New(
+			series.Strings([]string{"a", "bb"}),
+			series.Floats([]float64{1.5, 2.5}),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-detect-no-header-strings",
+		Config: `
+columns: [0, 6]
+`,
+		Input: "a     x\nbb    y\n",
+		Output: dataframe.New(
+			series.Strings([]string{"a", "bb"}),
+			series.Strings([]string{"x", "y"}),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-wide",
+		Config: `
+widths: [100000, 1]
+`,
+		Input: strings.Repeat(" ", 99999) + "1" + "2\n",
+		Output: dataframe.New(
+			series.Ints([]int{1}),
+			series.Ints([]int{2}),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-short-line",
+		Config: `
+widths: [3, 3]
+`,
+		Input: "  1  2\n  3\n",
+		Output: dataframe.New(
+			series.Ints([]int{1, 3}),
+			series.New([]interface{}{2, nil}, series.Int, "X1"),
+		),
+		Error: nil,
+	},
+}
+
+func TestFixedWidthRead(t *testing.T) {
+	for _, tt := range fixedWidthReadTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var config yaml.Node
+			err := yaml.Unmarshal([]byte(tt.Config), &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+
+			rw, err := NewFixedWidth(&config)
+			assert.Nil(err, "unexpected NewFixedWidth() error")
+			fn := func(string) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader(tt.Input)), nil
+			}
+			out, err := rw.ReadFromFn(fn)
+
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error != nil {
+				assert.Nil(out)
+			} else {
+				assert.Equal(tt.Output, *out)
+			}
+		})
+	}
+}
+
+func TestNewFixedWidth(t *testing.T) {
+	for _, tt := range []struct {
+		Name   string
+		Config string
+		Error  error
+	}{
+		{"good", "widths: [1, 2]", nil},
+		{"bad-both", "{widths: [1], columns: [0]}", ErrFixedWidthColumns},
+		{"bad-width", "widths: [1, 0]", common.ErrBadFieldValue},
+		{"bad-columns", "columns: [0, 4, 4]", common.ErrBadFieldValue},
+	} {
+		t.Run(tt.Name, func(t *testing.T) {
+			var config yaml.Node
+			err := yaml.Unmarshal([]byte(tt.Config), &config)
+			assert.Nil(t, err, "unexpected yaml.Unmarshal() error")
+			_, err = NewFixedWidth(&config)
+			assert.ErrorIs(t, err, tt.Error)
+		})
+	}
+}
+
+type fixedWidthWriteTest struct {
+	Name   string
+	Config string
+	Input  dataframe.DataFrame
+	Output string
+	Error  error
+}
+
+var fixedWidthWriteInput = dataframe.New(
+	series.New([]int{1, 20}, series.Int, "n"),
+	series.New([]string{"a", "bcd"}, series.String, "s"),
+)
+
+var fixedWidthWriteTests = []fixedWidthWriteTest{
+	{
+		Name:   "good-default",
+		Config: ``,
+		Input:  fixedWidthWriteInput,
+		Output: " n   s\n 1   a\n20 bcd\n",
+		Error:  nil,
+	},
+	{
+		Name: "good-widths-no-header",
+		Config: `
+widths: [4, 4]
+header: false
+`,
+		Input:  fixedWidthWriteInput,
+		Output: "   1   a\n  20 bcd\n",
+		Error:  nil,
+	},
+	{
+		Name: "good-columns",
+		Config: `
+columns: [2, 5]
+`,
+		Input:  fixedWidthWriteInput,
+		Output: "    n  s\n    1  a\n   20bcd\n",
+		Error:  nil,
+	},
+	{
+		Name: "good-float-format",
+		Config: `
+widths: [10, 10]
+float_format: "%.3E"
+header: false
+`,
+		Input: dataframe.New(
+			series.New([]float64{-1.234, 1}, series.Float, "x"),
+			series.New([]float64{-0.5678, 2.5}, series.Float, "y"),
+		),
+		Output: "-1.234E+00-5.678E-01\n 1.000E+00 2.500E+00\n",
+		Error:  nil,
+	},
+	{
+		Name: "bad-overflow",
+		Config: `
+widths: [2, 2]
+`,
+		Input:  fixedWidthWriteInput,
+		Output: "",
+		Error:  ErrFixedWidthOverflow,
+	},
+	{
+		Name: "bad-column-count",
+		Config: `
+widths: [2]
+`,
+		Input:  fixedWidthWriteInput,
+		Output: "",
+		Error:  common.ErrBadFieldValue,
+	},
+}
+
+func TestFixedWidthWrite(t *testing.T) {
+	for _, tt := range fixedWidthWriteTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var config yaml.Node
+			err := yaml.Unmarshal([]byte(tt.Config), &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+			rw, err := NewFixedWidth(&config)
+			assert.Nil(err, "unexpected NewFixedWidth() error")
+
+			var b bytes.Buffer
+			err = rw.write(&b, &tt.Input)
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error == nil {
+				assert.Equal(tt.Output, b.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package rw
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/Milover/post/internal/common"
+	datenc "github.com/Milover/post/internal/encoding/dat"
+	foamenc "github.com/Milover/post/internal/encoding/foam"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"gopkg.in/yaml.v3"
+)
+
+// foamField contains data needed for parsing an OpenFOAM field file,
+// e.g., '0/U', in the ASCII format.
+//
+// Either the 'internalField' values, or the 'value' entry of a boundary
+// patch are read, with one row per value, i.e., per cell or face.
+// A uniform field yields a single row.
+type foamField struct {
+	// File is the file path of the field file.
+	File string `yaml:"file"`
+	// Field is the name of the field.
+	// If left empty, the 'object' entry of the file header is used,
+	// or the base name of File if there is none.
+	Field string `yaml:"field"`
+	// Patch is the name of the boundary patch whose 'value' entry is
+	// read. If left empty, the 'internalField' is read.
+	Patch string `yaml:"patch"`
+
+	componentSpec `yaml:",inline"`
+}
+
+func defaultFoamField() *foamField {
+	return &foamField{
+		componentSpec: defaultComponentSpec(),
+	}
+}
+
+func NewFoamField(n *yaml.Node) (*foamField, error) {
+	rw := defaultFoamField()
+	if err := n.Decode(rw); err != nil {
+		return nil, fmt.Errorf("foam-field: %w", err)
+	}
+	if err := rw.componentSpec.validate(); err != nil {
+		return nil, fmt.Errorf("foam-field: %w", err)
+	}
+	return rw, nil
+}
+
+func (rw *foamField) Read() (*dataframe.DataFrame, error) {
+	fn := func(name string) (io.ReadCloser, error) {
+		return os.Open(name)
+	}
+	return rw.ReadFromFn(fn)
+}
+
+func (rw *foamField) ReadFromFn(fn ReaderFunc) (*dataframe.DataFrame, error) {
+	rc, err := fn(rw.File)
+	if err != nil {
+		return nil, fmt.Errorf("foam-field: %w", err)
+	}
+	defer rc.Close()
+	return rw.read(rc)
+}
+
+// name returns the field name.
+func (rw *foamField) name(d *foamenc.Dict) string {
+	if rw.Field != "" {
+		return rw.Field
+	}
+	if h, err := d.SubDict("FoamFile"); err == nil {
+		if obj, err := h.Word("object"); err == nil {
+			return obj
+		}
+	}
+	if rw.File != "" {
+		return filepath.Base(rw.File)
+	}
+	return "value"
+}
+
+// entry returns the field entry which should be read.
+func (rw *foamField) entry(d *foamenc.Dict) (*foamenc.Entry, error) {
+	if rw.Patch == "" {
+		return d.Lookup("internalField")
+	}
+	b, err := d.SubDict("boundaryField")
+	if err != nil {
+		return nil, err
+	}
+	p, err := b.SubDict(rw.Patch)
+	if err != nil {
+		return nil, err
+	}
+	return p.Lookup("value")
+}
+
+func (rw *foamField) read(in io.Reader) (*dataframe.DataFrame, error) {
+	d, err := foamenc.Read(in)
+	if err != nil {
+		return nil, fmt.Errorf("foam-field: %w", err)
+	}
+	e, err := rw.entry(d)
+	if err != nil {
+		return nil, fmt.Errorf("foam-field: %w", err)
+	}
+	if e.Dict != nil {
+		return nil, fmt.Errorf("foam-field: %w: %q", common.ErrBadFieldValue, e.Keyword)
+	}
+	f, err := foamenc.ParseField(e.Tokens)
+	if err != nil {
+		return nil, fmt.Errorf("foam-field: %w", err)
+	}
+
+	var shape datenc.Shape
+	if f.NComp > 1 {
+		shape = make(datenc.Shape, f.NComp)
+	}
+	n := f.Len()
+	ss := make([]series.Series, 0, f.NComp)
+	for i, name := range rw.names(rw.name(d), nil, shape) {
+		vals := make([]float64, n)
+		for j := range vals {
+			vals[j] = f.Values[j*f.NComp+i]
+		}
+		ss = append(ss, series.New(vals, series.Float, name))
+	}
+	df := dataframe.New(ss...)
+	if df.Error() != nil {
+		return nil, fmt.Errorf("foam-field: %w", df.Error())
+	}
+	return &df, nil
+}
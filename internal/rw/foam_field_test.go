@@ -0,0 +1,184 @@
+package rw
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	foamenc "github.com/Milover/post/internal/encoding/foam"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type foamFieldTest struct {
+	Name   string
+	Config string
+	Input  string
+	Output dataframe.DataFrame
+	Error  error
+}
+
+const foamFieldVector string = `FoamFile
+{
+    format      ascii;
+    class       volVectorField;
+    object      U;
+}
+dimensions      [0 1 -1 0 0 0 0];
+internalField   nonuniform List<vector> 2((1 2 3) (4 5 6));
+boundaryField
+{
+    inlet
+    {
+        type            fixedValue;
+        value           uniform (1 0 0);
+    }
+    "(top|bottom)"
+    {
+        type            noSlip;
+    }
+}
+`
+
+var foamFieldReadTests = []foamFieldTest{
+	{
+		Name:   "good-internal",
+		Config: ``,
+		Input:  foamFieldVector,
+		Output: dataframe.New(
+			series.New([]float64{1, 4}, series.Float, "U_x"),
+			series.New([]float64{2, 5}, series.Float, "U_y"),
+			series.New([]float64{3, 6}, series.Float, "U_z"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-patch",
+		Config: `
+field: velocity
+patch: inlet
+component_suffix: index
+`,
+		Input: foamFieldVector,
+		Output: dataframe.New(
+			series.New([]float64{1}, series.Float, "velocity_0"),
+			series.New([]float64{0}, series.Float, "velocity_1"),
+			series.New([]float64{0}, series.Float, "velocity_2"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-scalar",
+		Config: `
+file: 0/p
+`,
+		Input: "internalField uniform 1e5;\n",
+		Output: dataframe.New(
+			series.New([]float64{1e5}, series.Float, "p"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "bad-patch-no-value",
+		Config: `
+patch: top
+`,
+		Input:  foamFieldVector,
+		Output: dataframe.DataFrame{},
+		Error:  foamenc.ErrNotFound,
+	},
+	{
+		Name: "bad-patch",
+		Config: `
+patch: outlet
+`,
+		Input:  foamFieldVector,
+		Output: dataframe.DataFrame{},
+		Error:  foamenc.ErrNotFound,
+	},
+}
+
+func TestFoamFieldRead(t *testing.T) {
+	for _, tt := range foamFieldReadTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			raw, err := io.ReadAll(strings.NewReader(tt.Config))
+			assert.Nil(err, "unexpected io.ReadAll() error")
+			var config yaml.Node
+			err = yaml.Unmarshal(raw, &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+
+			rw, err := NewFoamField(&config)
+			assert.Nil(err, "unexpected NewFoamField() error")
+			out, err := rw.read(strings.NewReader(tt.Input))
+
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error != nil {
+				assert.Nil(out)
+			} else {
+				assert.Equal(tt.Output, *out)
+			}
+		})
+	}
+}
+
+var foamFieldSeriesReadTests = []foamFieldTest{
+	{
+		Name: "good-series-internal",
+		Config: `
+file: p
+directory: testdata/foam_fields
+format_spec:
+  type: foam-field
+`,
+		Output: dataframe.New(
+			series.New([]float64{0.1, 0.1, 0.1, 0.2, 0.2, 0.2}, series.Float, "time"),
+			series.New([]float64{1, 2, 3, 4, 5, 6}, series.Float, "p"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-series-patch",
+		Config: `
+file: p
+directory: testdata/foam_fields
+format_spec:
+  type: foam-field
+  type_spec:
+    patch: outlet
+`,
+		Output: dataframe.New(
+			series.New([]float64{0.1, 0.1, 0.2, 0.2}, series.Float, "time"),
+			series.New([]float64{0.5, 0.25, 1.5, 1.25}, series.Float, "p"),
+		),
+		Error: nil,
+	},
+}
+
+func TestFoamFieldSeriesRead(t *testing.T) {
+	for _, tt := range foamFieldSeriesReadTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			raw, err := io.ReadAll(strings.NewReader(tt.Config))
+			assert.Nil(err, "unexpected io.ReadAll() error")
+			var config yaml.Node
+			err = yaml.Unmarshal(raw, &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+
+			rw, err := NewTimeSeries(&config)
+			assert.Nil(err, "unexpected NewTimeSeries() error")
+			out, err := rw.Read()
+
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error != nil {
+				assert.Nil(out)
+			} else {
+				assert.Equal(tt.Output, *out)
+			}
+		})
+	}
+}
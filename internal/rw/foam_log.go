@@ -0,0 +1,263 @@
+package rw
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrFoamLogEmpty    = errors.New("foam-log: no time steps found")
+	ErrFoamLogResidual = errors.New("foam-log: mismatched residual tuple sizes")
+)
+
+var (
+	// residuals and iterations are scalars, or tuples for coupled solvers
+	foamLogSolverRegexp = regexp.MustCompile(
+		`^(\S+):\s+Solving for (\S+), Initial residual = (\([^)]*\)|[^,\s]+), Final residual = (\([^)]*\)|[^,\s]+), No Iterations (\([^)]*\)|\d+)`)
+	foamLogCourantRegexp = regexp.MustCompile(
+		`^(.*)Courant Number mean: (\S+) max: (\S+)`)
+	foamLogContinuityRegexp = regexp.MustCompile(
+		`^time step continuity errors : sum local = ([^,\s]+), global = ([^,\s]+), cumulative = (\S+)`)
+	foamLogExecutionRegexp = regexp.MustCompile(
+		`^ExecutionTime = (\S+) s\s+ClockTime = (\S+) s`)
+)
+
+// foamLog contains data needed for parsing an OpenFOAM solver log file,
+// e.g., 'log.pimpleFoam'.
+//
+// Each time step of the log yields a single row, containing the time,
+// the initial and final residuals and the number of iterations for each
+// solved field and solver, the Courant number mean and max values, the time
+// step continuity errors, the time step size and the execution and clock
+// times. If a field is solved for several times by the same solver during
+// a time step, e.g., due to outer correctors, the first initial residual,
+// the last final residual and the total number of iterations are kept.
+// The tuple residuals of coupled solvers are expanded into components.
+// Missing values are set to NaN.
+type foamLog struct {
+	// File is the file path of the log file.
+	File string `yaml:"file"`
+	// TimeName is the name of the time field.
+	// If left empty it is set to 'time'.
+	TimeName string `yaml:"time_name"`
+
+	componentSpec `yaml:",inline"`
+}
+
+func defaultFoamLog() *foamLog {
+	return &foamLog{
+		TimeName:      "time",
+		componentSpec: defaultComponentSpec(),
+	}
+}
+
+func NewFoamLog(n *yaml.Node) (*foamLog, error) {
+	rw := defaultFoamLog()
+	if err := n.Decode(rw); err != nil {
+		return nil, fmt.Errorf("foam-log: %w", err)
+	}
+	if err := rw.componentSpec.validate(); err != nil {
+		return nil, fmt.Errorf("foam-log: %w", err)
+	}
+	return rw, nil
+}
+
+// parseFoamLogValues parses a scalar, or a parenthesised tuple, e.g.,
+// '(0.1 0.2 0.3)', of values.
+func parseFoamLogValues(s string) ([]float64, error) {
+	fields := []string{s}
+	if strings.HasPrefix(s, "(") {
+		fields = strings.Fields(strings.Trim(s, "()"))
+	}
+	vals := make([]float64, len(fields))
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = v
+	}
+	return vals, nil
+}
+
+func (rw *foamLog) Read() (*dataframe.DataFrame, error) {
+	fn := func(name string) (io.ReadCloser, error) {
+		return os.Open(name)
+	}
+	return rw.ReadFromFn(fn)
+}
+
+func (rw *foamLog) ReadFromFn(fn ReaderFunc) (*dataframe.DataFrame, error) {
+	rc, err := fn(rw.File)
+	if err != nil {
+		return nil, fmt.Errorf("foam-log: %w", err)
+	}
+	defer rc.Close()
+	return rw.read(rc)
+}
+
+// logTable is a helper for building a table of time step values,
+// where the field order is the order in which the fields first appear.
+type logTable struct {
+	names []string
+	known map[string]bool
+	rows  []map[string]float64
+	row   map[string]float64
+}
+
+// set sets the value of a field in the current row.
+func (t *logTable) set(name string, v float64) {
+	if t.row == nil {
+		t.row = make(map[string]float64)
+	}
+	if t.known == nil {
+		t.known = make(map[string]bool)
+	}
+	if !t.known[name] {
+		t.known[name] = true
+		t.names = append(t.names, name)
+	}
+	t.row[name] = v
+}
+
+// setOnce sets the value of a field in the current row,
+// unless it has already been set.
+func (t *logTable) setOnce(name string, v float64) {
+	if _, found := t.row[name]; !found {
+		t.set(name, v)
+	}
+}
+
+// add adds v to the value of a field in the current row.
+func (t *logTable) add(name string, v float64) {
+	t.set(name, t.row[name]+v)
+}
+
+// flush finishes the current row, if it contains the time field.
+func (t *logTable) flush(timeName string) {
+	if _, found := t.row[timeName]; found {
+		t.rows = append(t.rows, t.row)
+		t.row = nil
+	}
+}
+
+func (rw *foamLog) read(in io.Reader) (*dataframe.DataFrame, error) {
+	var t logTable
+	parse := func(s string) (float64, error) {
+		return strconv.ParseFloat(strings.TrimSuffix(s, "s"), 64)
+	}
+	sc := bufio.NewScanner(in)
+	sc.Buffer(nil, 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case strings.HasPrefix(line, "Time = "):
+			fields := strings.Fields(strings.TrimPrefix(line, "Time = "))
+			if len(fields) == 0 {
+				continue
+			}
+			v, err := parse(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("foam-log: %w", err)
+			}
+			// no 'ExecutionTime' was written for the previous time step
+			t.flush(rw.TimeName)
+			t.set(rw.TimeName, v)
+		case strings.HasPrefix(line, "deltaT = "):
+			if v, err := parse(strings.TrimPrefix(line, "deltaT = ")); err == nil {
+				t.set("delta_t", v)
+			}
+		case foamLogSolverRegexp.MatchString(line):
+			m := foamLogSolverRegexp.FindStringSubmatch(line)
+			initial, errI := parseFoamLogValues(m[3])
+			final, errF := parseFoamLogValues(m[4])
+			iters, errN := parseFoamLogValues(m[5])
+			if err := errors.Join(errI, errF, errN); err != nil {
+				return nil, fmt.Errorf("foam-log: %w", err)
+			}
+			n := len(initial)
+			if len(final) != n || (len(iters) != n && len(iters) != 1) {
+				return nil, fmt.Errorf("foam-log: %w: %q", ErrFoamLogResidual, line)
+			}
+			for i := range initial {
+				name := m[2]
+				if n > 1 {
+					name += rw.ComponentSeparator + rw.suffix(n, i)
+				}
+				name += "_" + m[1]
+				t.setOnce(name+"_initial", initial[i])
+				t.set(name+"_final", final[i])
+				t.add(name+"_iterations", iters[min(i, len(iters)-1)])
+			}
+		case foamLogCourantRegexp.MatchString(line):
+			m := foamLogCourantRegexp.FindStringSubmatch(line)
+			prefix := strings.ToLower(strings.Join(strings.Fields(m[1]), "_"))
+			if prefix != "" {
+				prefix += "_"
+			}
+			if mean, err := parse(m[2]); err == nil {
+				t.set(prefix+"courant_mean", mean)
+			}
+			if mx, err := parse(m[3]); err == nil {
+				t.set(prefix+"courant_max", mx)
+			}
+		case foamLogContinuityRegexp.MatchString(line):
+			m := foamLogContinuityRegexp.FindStringSubmatch(line)
+			for i, name := range []string{"local", "global", "cumulative"} {
+				if v, err := parse(m[i+1]); err == nil {
+					t.set("continuity_"+name, v)
+				}
+			}
+		case foamLogExecutionRegexp.MatchString(line):
+			m := foamLogExecutionRegexp.FindStringSubmatch(line)
+			if v, err := parse(m[1]); err == nil {
+				t.set("execution_time", v)
+			}
+			if v, err := parse(m[2]); err == nil {
+				t.set("clock_time", v)
+			}
+			t.flush(rw.TimeName)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("foam-log: %w", err)
+	}
+	t.flush(rw.TimeName)
+	if len(t.rows) == 0 {
+		return nil, ErrFoamLogEmpty
+	}
+
+	ss := make([]series.Series, 0, len(t.names))
+	for _, name := range t.names {
+		vals := make([]float64, len(t.rows))
+		for i, row := range t.rows {
+			v, found := row[name]
+			if !found {
+				v = math.NaN()
+			}
+			vals[i] = v
+		}
+		s := series.New(vals, series.Float, name)
+		if name == rw.TimeName {
+			ss = append([]series.Series{s}, ss...)
+		} else {
+			ss = append(ss, s)
+		}
+	}
+	df := dataframe.New(ss...)
+	if df.Error() != nil {
+		return nil, fmt.Errorf("foam-log: %w", df.Error())
+	}
+	return &df, nil
+}
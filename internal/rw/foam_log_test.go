@@ -0,0 +1,202 @@
+package rw
+
+import (
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type foamLogTest struct {
+	Name   string
+	Config string
+	Input  string
+	Output dataframe.DataFrame
+	Error  error
+}
+
+const foamLogPimple string = `
+Create time
+
+Courant Number mean: 0 max: 0
+
+Starting time loop
+
+Courant Number mean: 0.1 max: 0.5
+deltaT = 0.001
+Time = 0.001
+
+PIMPLE: iteration 1
+smoothSolver:  Solving for Ux, Initial residual = 1, Final residual = 0.001, No Iterations 2
+DICPCG:  Solving for p, Initial residual = 1, Final residual = 0.01, No Iterations 10
+time step continuity errors : sum local = 1e-05, global = 1e-20, cumulative = 1e-20
+DICPCG:  Solving for p, Initial residual = 0.1, Final residual = 0.001, No Iterations 5
+time step continuity errors : sum local = 2e-06, global = 2e-20, cumulative = 3e-20
+ExecutionTime = 0.5 s  ClockTime = 1 s
+
+Courant Number mean: 0.2 max: 0.6
+deltaT = 0.002
+Time = 0.003
+
+PIMPLE: iteration 1
+DICPCG:  Solving for p, Initial residual = 0.5, Final residual = 0.005, No Iterations 3
+time step continuity errors : sum local = 3e-06, global = 3e-20, cumulative = 6e-20
+ExecutionTime = 0.75 s  ClockTime = 1 s
+
+End
+`
+
+const foamLogSimple string = `
+Starting time loop
+
+Time = 1
+
+smoothSolver:  Solving for Ux, Initial residual = 1, Final residual = 0.1, No Iterations 3
+GAMG:  Solving for p, Initial residual = 1, Final residual = 0.01, No Iterations 7
+time step continuity errors : sum local = 1, global = 0.1, cumulative = 0.1
+ExecutionTime = 0.1 s  ClockTime = 0 s
+
+Time = 2
+
+smoothSolver:  Solving for Ux, Initial residual = 0.5, Final residual = 0.05, No Iterations 2
+GAMG:  Solving for p, Initial residual = 0.25, Final residual = 0.02, No Iterations 6
+time step continuity errors : sum local = 0.5, global = 0.05, cumulative = 0.15
+ExecutionTime = 0.2 s  ClockTime = 0 s
+
+End
+`
+
+const foamLogSolvers string = `
+Time = 1
+
+DILUPBiCGStab:  Solving for U, Initial residual = (0.1 0.2 0.3), Final residual = (0.01 0.02 0.03), No Iterations (2 3 4)
+GAMG:  Solving for p, Initial residual = 1, Final residual = 0.1, No Iterations 5
+PCG:  Solving for p, Initial residual = 0.1, Final residual = 0.001, No Iterations 20
+ExecutionTime = 0.1 s  ClockTime = 0 s
+
+Time = 2
+
+DILUPBiCGStab:  Solving for U, Initial residual = (0.4 0.5 0.6), Final residual = (0.04 0.05 0.06), No Iterations 5
+GAMG:  Solving for p, Initial residual = 0.5, Final residual = 0.05, No Iterations 4
+PCG:  Solving for p, Initial residual = 0.05, Final residual = 0.0005, No Iterations 10
+ExecutionTime = 0.2 s  ClockTime = 0 s
+`
+
+var foamLogReadTests = []foamLogTest{
+	{
+		Name:   "good-simple",
+		Config: "",
+		Input:  foamLogSimple,
+		Output: dataframe.New(
+			series.New([]float64{1, 2}, series.Float, "time"),
+			series.New([]float64{1, 0.5}, series.Float, "Ux_smoothSolver_initial"),
+			series.New([]float64{0.1, 0.05}, series.Float, "Ux_smoothSolver_final"),
+			series.New([]float64{3, 2}, series.Float, "Ux_smoothSolver_iterations"),
+			series.New([]float64{1, 0.25}, series.Float, "p_GAMG_initial"),
+			series.New([]float64{0.01, 0.02}, series.Float, "p_GAMG_final"),
+			series.New([]float64{7, 6}, series.Float, "p_GAMG_iterations"),
+			series.New([]float64{1, 0.5}, series.Float, "continuity_local"),
+			series.New([]float64{0.1, 0.05}, series.Float, "continuity_global"),
+			series.New([]float64{0.1, 0.15}, series.Float, "continuity_cumulative"),
+			series.New([]float64{0.1, 0.2}, series.Float, "execution_time"),
+			series.New([]float64{0, 0}, series.Float, "clock_time"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-pimple",
+		Config: `
+time_name: t
+`,
+		Input: foamLogPimple,
+		Output: dataframe.New(
+			series.New([]float64{0.001, 0.003}, series.Float, "t"),
+			series.New([]float64{0.1, 0.2}, series.Float, "courant_mean"),
+			series.New([]float64{0.5, 0.6}, series.Float, "courant_max"),
+			series.New([]float64{0.001, 0.002}, series.Float, "delta_t"),
+			series.New([]float64{1, math.NaN()}, series.Float, "Ux_smoothSolver_initial"),
+			series.New([]float64{0.001, math.NaN()}, series.Float, "Ux_smoothSolver_final"),
+			series.New([]float64{2, math.NaN()}, series.Float, "Ux_smoothSolver_iterations"),
+			series.New([]float64{1, 0.5}, series.Float, "p_DICPCG_initial"),
+			series.New([]float64{0.001, 0.005}, series.Float, "p_DICPCG_final"),
+			series.New([]float64{15, 3}, series.Float, "p_DICPCG_iterations"),
+			series.New([]float64{2e-06, 3e-06}, series.Float, "continuity_local"),
+			series.New([]float64{2e-20, 3e-20}, series.Float, "continuity_global"),
+			series.New([]float64{3e-20, 6e-20}, series.Float, "continuity_cumulative"),
+			series.New([]float64{0.5, 0.75}, series.Float, "execution_time"),
+			series.New([]float64{1, 1}, series.Float, "clock_time"),
+		),
+		Error: nil,
+	},
+	{
+		Name:   "good-solvers",
+		Config: "",
+		Input:  foamLogSolvers,
+		Output: dataframe.New(
+			series.New([]float64{1, 2}, series.Float, "time"),
+			series.New([]float64{0.1, 0.4}, series.Float, "U_x_DILUPBiCGStab_initial"),
+			series.New([]float64{0.01, 0.04}, series.Float, "U_x_DILUPBiCGStab_final"),
+			series.New([]float64{2, 5}, series.Float, "U_x_DILUPBiCGStab_iterations"),
+			series.New([]float64{0.2, 0.5}, series.Float, "U_y_DILUPBiCGStab_initial"),
+			series.New([]float64{0.02, 0.05}, series.Float, "U_y_DILUPBiCGStab_final"),
+			series.New([]float64{3, 5}, series.Float, "U_y_DILUPBiCGStab_iterations"),
+			series.New([]float64{0.3, 0.6}, series.Float, "U_z_DILUPBiCGStab_initial"),
+			series.New([]float64{0.03, 0.06}, series.Float, "U_z_DILUPBiCGStab_final"),
+			series.New([]float64{4, 5}, series.Float, "U_z_DILUPBiCGStab_iterations"),
+			series.New([]float64{1, 0.5}, series.Float, "p_GAMG_initial"),
+			series.New([]float64{0.1, 0.05}, series.Float, "p_GAMG_final"),
+			series.New([]float64{5, 4}, series.Float, "p_GAMG_iterations"),
+			series.New([]float64{0.1, 0.05}, series.Float, "p_PCG_initial"),
+			series.New([]float64{0.001, 0.0005}, series.Float, "p_PCG_final"),
+			series.New([]float64{20, 10}, series.Float, "p_PCG_iterations"),
+			series.New([]float64{0.1, 0.2}, series.Float, "execution_time"),
+			series.New([]float64{0, 0}, series.Float, "clock_time"),
+		),
+		Error: nil,
+	},
+	{
+		Name:   "bad-tuple",
+		Config: "",
+		Input:  "Time = 1\nGAMG:  Solving for U, Initial residual = (1 2 3), Final residual = (1 2), No Iterations 1\n",
+		Output: dataframe.DataFrame{},
+		Error:  ErrFoamLogResidual,
+	},
+	{
+		Name:   "bad-empty",
+		Config: "",
+		Input:  "Starting time loop\n\nEnd\n",
+		Output: dataframe.DataFrame{},
+		Error:  ErrFoamLogEmpty,
+	},
+}
+
+func TestFoamLogRead(t *testing.T) {
+	for _, tt := range foamLogReadTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			raw, err := io.ReadAll(strings.NewReader(tt.Config))
+			assert.Nil(err, "unexpected io.ReadAll() error")
+			var config yaml.Node
+			err = yaml.Unmarshal(raw, &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+
+			rw, err := NewFoamLog(&config)
+			assert.Nil(err, "unexpected NewFoamLog() error")
+			out, err := rw.read(strings.NewReader(tt.Input))
+
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error != nil {
+				assert.Nil(out)
+			} else {
+				assert.Equal(tt.Output.Names(), out.Names())
+				assert.Equal(tt.Output.Records(), out.Records())
+			}
+		})
+	}
+}
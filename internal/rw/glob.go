@@ -0,0 +1,298 @@
+package rw
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Milover/post/internal/archived"
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrGlobNoMatch = errors.New("glob: no files match the pattern")
+	ErrGlobTag     = errors.New("glob: tag regexp does not match the path")
+	ErrGlobFields  = errors.New("glob: files differ in fields")
+)
+
+// glob contains data needed for reading all files matching a glob
+// pattern, using the same input type, and row-binding the results.
+//
+// The pattern syntax is that of path.Match, extended with '**', which
+// matches any number of directories, e.g., 'runs/**/force.dat'.
+// Files are read in lexical order, and each row is tagged with the path of
+// the file from which it was read, or with fields extracted from the path
+// by a regular expression.
+type glob struct {
+	// Pattern is the glob pattern of the files which are read,
+	// e.g., 'runs/*/postProcessing/forces/0/force.dat'.
+	Pattern string `yaml:"pattern"`
+	// TagName is the name of the field holding the file path.
+	// If left empty it is set to 'source'. If set to an empty string
+	// explicitly, rows are not tagged with the file path.
+	TagName string `yaml:"tag_name"`
+	// TagRegexp is a regular expression matched against the file path.
+	// If defined, each named capture group becomes a (string) field, instead
+	// of the TagName field. If there are no named capture groups,
+	// the TagName field holds the first capture group, or the whole match
+	// if there are no capture groups.
+	TagRegexp string `yaml:"tag_regexp"`
+	// FormatSpec is the config for the input type of the matched files,
+	// e.g., if the files are CSV files, FormatSpec would define a config
+	// for a CSV input type.
+	FormatSpec Config `yaml:"format_spec"`
+
+	re *regexp.Regexp
+}
+
+func defaultGlob() *glob {
+	return &glob{
+		TagName: "source",
+	}
+}
+
+func NewGlob(n *yaml.Node) (*glob, error) {
+	rw := defaultGlob()
+	if err := n.Decode(rw); err != nil {
+		return nil, fmt.Errorf("glob: %w", err)
+	}
+	if rw.Pattern == "" {
+		return nil, fmt.Errorf("glob: %w: %q", common.ErrUnsetField, "pattern")
+	}
+	if _, err := path.Match(strings.ReplaceAll(rw.Pattern, "**", "*"), ""); err != nil {
+		return nil, fmt.Errorf("glob: %w: %q: %v", common.ErrBadFieldValue, "pattern", err)
+	}
+	if rw.TagRegexp != "" {
+		var err error
+		if rw.re, err = regexp.Compile(rw.TagRegexp); err != nil {
+			return nil, fmt.Errorf("glob: %w: %q: %v", common.ErrBadFieldValue, "tag_regexp", err)
+		}
+	}
+	return rw, nil
+}
+
+// hasMeta reports whether s contains glob metacharacters.
+func hasMeta(s string) bool {
+	return strings.ContainsAny(s, `*?[\`)
+}
+
+// splitPattern splits a glob pattern into a static directory prefix,
+// which contains no metacharacters, and the remaining pattern.
+func splitPattern(pattern string) (string, string) {
+	segs := strings.Split(pattern, "/")
+	i := 0
+	for i < len(segs)-1 && !hasMeta(segs[i]) {
+		i++
+	}
+	prefix := strings.Join(segs[:i], "/")
+	if prefix == "" && strings.HasPrefix(pattern, "/") {
+		prefix = "/"
+	}
+	if prefix == "" {
+		prefix = "."
+	}
+	return prefix, strings.Join(segs[i:], "/")
+}
+
+// matchSegments reports whether the path segments name match
+// the pattern segments pat. If partial is true, it reports whether
+// name could be the leading part of a matching path.
+func matchSegments(pat, name []string, partial bool) bool {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			// '**' matches any number of segments
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pat[1:], name[i:], partial) {
+					return true
+				}
+			}
+			return partial
+		}
+		if len(name) == 0 {
+			return partial
+		}
+		if ok, _ := path.Match(pat[0], name[0]); !ok {
+			return false
+		}
+		pat, name = pat[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// match returns the paths of all regular files in fsys which match
+// pattern, in lexical order.
+func match(fsys fs.FS, pattern string) ([]string, error) {
+	pat := strings.Split(pattern, "/")
+	var matches []string
+	walkFn := func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == "." {
+			return nil
+		}
+		name := strings.Split(p, "/")
+		if d.IsDir() {
+			if !matchSegments(pat, name, true) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if matchSegments(pat, name, false) {
+			matches = append(matches, p)
+		}
+		return nil
+	}
+	if err := fs.WalkDir(fsys, ".", walkFn); err != nil {
+		return nil, err
+	}
+	slices.Sort(matches)
+	return matches, nil
+}
+
+func (rw *glob) Read() (*dataframe.DataFrame, error) {
+	prefix, pattern := splitPattern(rw.Pattern)
+	if _, err := os.Stat(prefix); err != nil {
+		return nil, fmt.Errorf("glob: %w", err)
+	}
+	return rw.read(os.DirFS(filepath.FromSlash(prefix)), prefix, pattern)
+}
+
+func (rw *glob) ReadFromFn(fn ReaderFunc) (*dataframe.DataFrame, error) {
+	in, err := fn(".")
+	if err != nil {
+		return nil, fmt.Errorf("glob: %w", err)
+	}
+	fsys, ok := in.(fs.FS)
+	if !ok {
+		in.Close()
+		return nil, fmt.Errorf("glob: %w to 'fs.FS'", common.ErrBadCast)
+	}
+	return rw.read(fsys, ".", rw.Pattern)
+}
+
+// tags returns the tag fields of a file path.
+func (rw *glob) tags(p string) ([]string, []string, error) {
+	if rw.re == nil {
+		if rw.TagName == "" {
+			return nil, nil, nil
+		}
+		return []string{rw.TagName}, []string{p}, nil
+	}
+	m := rw.re.FindStringSubmatch(p)
+	if m == nil {
+		return nil, nil, fmt.Errorf("%w: %q", ErrGlobTag, p)
+	}
+	var names, values []string
+	for i, name := range rw.re.SubexpNames() {
+		if i > 0 && name != "" {
+			names = append(names, name)
+			values = append(values, m[i])
+		}
+	}
+	if len(names) == 0 && rw.TagName != "" {
+		names = []string{rw.TagName}
+		values = []string{m[min(1, len(m)-1)]}
+	}
+	return names, values, nil
+}
+
+// globSpec returns a glob input type config wrapping config, if the 'file'
+// of config contains glob metacharacters, in which case it is used as
+// the glob pattern. Otherwise config is returned.
+func globSpec(config *Config) (*Config, error) {
+	n := &config.TypeSpec
+	if n.Kind != yaml.MappingNode {
+		return config, nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.Value != "file" || v.Kind != yaml.ScalarNode || !hasMeta(v.Value) {
+			continue
+		}
+		// the wrapped config without the 'file' field
+		inner := *config
+		inner.TypeSpec = *n
+		inner.TypeSpec.Content = slices.Delete(slices.Clone(n.Content), i, i+2)
+
+		var spec yaml.Node
+		err := spec.Encode(struct {
+			Pattern    string `yaml:"pattern"`
+			FormatSpec Config `yaml:"format_spec"`
+		}{v.Value, inner})
+		if err != nil {
+			return nil, err
+		}
+		return &Config{Type: "glob", TypeSpec: spec}, nil
+	}
+	return config, nil
+}
+
+// read reads all files matching pattern from fsys, where prefix is
+// the path of the root of fsys.
+func (rw *glob) read(fsys fs.FS, prefix, pattern string) (*dataframe.DataFrame, error) {
+	paths, err := match(fsys, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("glob: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrGlobNoMatch, rw.Pattern)
+	}
+	archived.Prefetch(fsys, paths)
+
+	dfs := make([]*dataframe.DataFrame, 0, len(paths))
+	for _, p := range paths {
+		if common.Verbose {
+			log.Printf("glob: reading: %v", path.Join(prefix, p))
+		}
+		fn := func(_ string) (io.ReadCloser, error) {
+			return fsys.Open(p)
+		}
+		temp, err := ReadFromFn(fn, &rw.FormatSpec)
+		if err != nil {
+			return nil, fmt.Errorf("glob: %w: in file: %v", err, p)
+		}
+		names, values, err := rw.tags(path.Join(prefix, p))
+		if err != nil {
+			return nil, err
+		}
+		if len(names) > 0 {
+			ss := make([]series.Series, len(names))
+			for i := range names {
+				col := make([]string, temp.Nrow())
+				for j := range col {
+					col[j] = values[i]
+				}
+				ss[i] = series.New(col, series.String, names[i])
+			}
+			*temp = dataframe.New(ss...).CBind(*temp)
+			if temp.Error() != nil {
+				return nil, fmt.Errorf("glob: %w: in file: %v", temp.Error(), p)
+			}
+		}
+		if len(dfs) > 0 && !sameFields(temp.Names(), dfs[0].Names()) {
+			return nil, fmt.Errorf("glob: %w: %q != %q: in file: %v",
+				ErrGlobFields, temp.Names(), dfs[0].Names(), p)
+		}
+		dfs = append(dfs, temp)
+	}
+	if common.Verbose {
+		log.Printf("glob: concatenating %v files", len(dfs))
+	}
+	df, err := rbind(dfs)
+	if err != nil {
+		return nil, fmt.Errorf("glob: %w", err)
+	}
+	return &df, nil
+}
@@ -0,0 +1,182 @@
+package rw
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type globTest struct {
+	Name   string
+	Config string
+	Output dataframe.DataFrame
+	Error  error
+}
+
+// globExpected returns the expected output for the glob tests, i.e.,
+// the 'data.csv' files of the 'foam_series.good' test data, tagged with
+// the tag fields.
+func globExpected(tags ...series.Series) dataframe.DataFrame {
+	return dataframe.New(append(tags,
+		expectedSeries.Col("x"),
+		expectedSeries.Col("y"),
+	)...)
+}
+
+// repeat returns a slice containing each of s repeated n times.
+func repeat(n int, s ...string) []string {
+	var out []string
+	for _, v := range s {
+		for i := 0; i < n; i++ {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+var globReadTests = []globTest{
+	{
+		Name: "good-path",
+		Config: `
+type: glob
+type_spec:
+  pattern: testdata/foam_series.good/*/data.csv
+  format_spec:
+    type: csv
+`,
+		Output: globExpected(
+			series.New(repeat(6,
+				"testdata/foam_series.good/0.1/data.csv",
+				"testdata/foam_series.good/0.2/data.csv",
+				"testdata/foam_series.good/0.3/data.csv",
+			), series.String, "source"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-doublestar-regexp",
+		Config: `
+type: glob
+type_spec:
+  pattern: testdata/**/foam_series.good/**/data.csv
+  tag_regexp: 'foam_series\.(?P<case>[a-z]+)/(?P<time>[0-9.]+)/'
+  format_spec:
+    type: csv
+`,
+		Output: globExpected(
+			series.New(repeat(18, "good"), series.String, "case"),
+			series.New(repeat(6, "0.1", "0.2", "0.3"), series.String, "time"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-regexp-unnamed",
+		Config: `
+type: glob
+type_spec:
+  pattern: testdata/foam_series.good/*/data.csv
+  tag_name: time
+  tag_regexp: '/([0-9.]+)/'
+  format_spec:
+    type: csv
+`,
+		Output: globExpected(
+			series.New(repeat(6, "0.1", "0.2", "0.3"), series.String, "time"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-archive",
+		Config: `
+type: archive
+type_spec:
+  file: testdata/archive.zip
+  format_spec:
+    type: glob
+    type_spec:
+      pattern: archive/foam_series.*/**/data.csv
+      tag_name: ''
+      format_spec:
+        type: csv
+`,
+		Output: globExpected(),
+		Error:  nil,
+	},
+	{
+		Name: "bad-no-match",
+		Config: `
+type: glob
+type_spec:
+  pattern: testdata/foam_series.good/*/missing.csv
+  format_spec:
+    type: csv
+`,
+		Output: dataframe.DataFrame{},
+		Error:  ErrGlobNoMatch,
+	},
+	{
+		Name: "bad-tag",
+		Config: `
+type: glob
+type_spec:
+  pattern: testdata/foam_series.good/*/data.csv
+  tag_regexp: 'nomatch'
+  format_spec:
+    type: csv
+`,
+		Output: dataframe.DataFrame{},
+		Error:  ErrGlobTag,
+	},
+}
+
+func TestGlobRead(t *testing.T) {
+	for _, tt := range globReadTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			raw, err := io.ReadAll(strings.NewReader(tt.Config))
+			assert.Nil(err, "unexpected io.ReadAll() error")
+			var config Config
+			err = yaml.Unmarshal(raw, &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+			out, err := Read(&config)
+
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error != nil {
+				assert.Nil(out)
+			} else {
+				assert.Equal(tt.Output, *out)
+			}
+		})
+	}
+}
+
+func TestMatchSegments(t *testing.T) {
+	for _, tt := range []struct {
+		Pattern string
+		Path    string
+		Partial bool
+		Match   bool
+	}{
+		{"runs/*/force.dat", "runs/a/force.dat", false, true},
+		{"runs/*/force.dat", "runs/a/b/force.dat", false, false},
+		{"runs/**/force.dat", "runs/force.dat", false, true},
+		{"runs/**/force.dat", "runs/a/b/force.dat", false, true},
+		{"runs/**", "runs/a/b", false, true},
+		{"runs/*/force.dat", "runs/a", true, true},
+		{"runs/*/force.dat", "other", true, false},
+		{"**/force.dat", "a/b", true, true},
+	} {
+		t.Run(tt.Pattern+":"+tt.Path, func(t *testing.T) {
+			assert.Equal(t, tt.Match, matchSegments(
+				strings.Split(tt.Pattern, "/"),
+				strings.Split(tt.Path, "/"),
+				tt.Partial))
+		})
+	}
+}
@@ -34,17 +34,37 @@ type ReaderFactory func(*yaml.Node) (Reader, error)
 type ReaderOutOfFactory func(*yaml.Node) (ReaderFromFn, error)
 
 var Readers = map[string]ReaderFactory{
-	"csv":         func(n *yaml.Node) (Reader, error) { return NewCsv(n) },
-	"dat":         func(n *yaml.Node) (Reader, error) { return NewDat(n) },
-	"time-series": func(n *yaml.Node) (Reader, error) { return NewTimeSeries(n) },
-	"ram":         func(n *yaml.Node) (Reader, error) { return NewRam(n) },
-	"multiple":    func(n *yaml.Node) (Reader, error) { return NewMultiple(n) },
-	"archive":     func(n *yaml.Node) (Reader, error) { return NewArchive(n) },
+	"csv":            func(n *yaml.Node) (Reader, error) { return NewCsv(n) },
+	"dat":            func(n *yaml.Node) (Reader, error) { return NewDat(n) },
+	"fixed-width":    func(n *yaml.Node) (Reader, error) { return NewFixedWidth(n) },
+	"time-series":    func(n *yaml.Node) (Reader, error) { return NewTimeSeries(n) },
+	"ram":            func(n *yaml.Node) (Reader, error) { return NewRam(n) },
+	"multiple":       func(n *yaml.Node) (Reader, error) { return NewMultiple(n) },
+	"archive":        func(n *yaml.Node) (Reader, error) { return NewArchive(n) },
+	"foam-field":     func(n *yaml.Node) (Reader, error) { return NewFoamField(n) },
+	"foam-log":       func(n *yaml.Node) (Reader, error) { return NewFoamLog(n) },
+	"glob":           func(n *yaml.Node) (Reader, error) { return NewGlob(n) },
+	"json":           func(n *yaml.Node) (Reader, error) { return NewJSON(n) },
+	"ndjson":         func(n *yaml.Node) (Reader, error) { return NewNDJSON(n) },
+	"probes":         func(n *yaml.Node) (Reader, error) { return NewProbes(n) },
+	"restart-series": func(n *yaml.Node) (Reader, error) { return NewRestartSeries(n) },
+	"sets":           func(n *yaml.Node) (Reader, error) { return NewSets(n) },
+	"vtk":            func(n *yaml.Node) (Reader, error) { return NewVtk(n) },
 }
 var ReadersFromFn = map[string]ReaderOutOfFactory{
-	"csv":         func(n *yaml.Node) (ReaderFromFn, error) { return NewCsv(n) },
-	"dat":         func(n *yaml.Node) (ReaderFromFn, error) { return NewDat(n) },
-	"time-series": func(n *yaml.Node) (ReaderFromFn, error) { return NewTimeSeries(n) },
+	"csv":            func(n *yaml.Node) (ReaderFromFn, error) { return NewCsv(n) },
+	"dat":            func(n *yaml.Node) (ReaderFromFn, error) { return NewDat(n) },
+	"fixed-width":    func(n *yaml.Node) (ReaderFromFn, error) { return NewFixedWidth(n) },
+	"time-series":    func(n *yaml.Node) (ReaderFromFn, error) { return NewTimeSeries(n) },
+	"foam-field":     func(n *yaml.Node) (ReaderFromFn, error) { return NewFoamField(n) },
+	"foam-log":       func(n *yaml.Node) (ReaderFromFn, error) { return NewFoamLog(n) },
+	"glob":           func(n *yaml.Node) (ReaderFromFn, error) { return NewGlob(n) },
+	"json":           func(n *yaml.Node) (ReaderFromFn, error) { return NewJSON(n) },
+	"ndjson":         func(n *yaml.Node) (ReaderFromFn, error) { return NewNDJSON(n) },
+	"probes":         func(n *yaml.Node) (ReaderFromFn, error) { return NewProbes(n) },
+	"restart-series": func(n *yaml.Node) (ReaderFromFn, error) { return NewRestartSeries(n) },
+	"sets":           func(n *yaml.Node) (ReaderFromFn, error) { return NewSets(n) },
+	"vtk":            func(n *yaml.Node) (ReaderFromFn, error) { return NewVtk(n) },
 }
 
 // DecodeRuneOrDefault tries to decode a rune from a string and returns the
@@ -73,11 +93,15 @@ func Read(config *Config) (*dataframe.DataFrame, error) {
 	if err != nil {
 		return nil, err
 	}
+	raw := setRaw(r, config)
 	df, err := r.Read()
 	if err != nil {
 		return nil, err
 	}
-	return SetNames(df, config.Fields)
+	if df, err = SetNames(df, config.Fields); err != nil {
+		return nil, err
+	}
+	return applySchema(df, config, raw)
 }
 
 // ReadFromFn reads a dataframe.DataFrame from a ReaderFromFn supplied with fn
@@ -91,11 +115,15 @@ func ReadFromFn(fn ReaderFunc, config *Config) (*dataframe.DataFrame, error) {
 	if err != nil {
 		return nil, err
 	}
+	raw := setRaw(r, config)
 	df, err := r.ReadFromFn(fn)
 	if err != nil {
 		return nil, err
 	}
-	return SetNames(df, config.Fields)
+	if df, err = SetNames(df, config.Fields); err != nil {
+		return nil, err
+	}
+	return applySchema(df, config, raw)
 }
 
 func SetNames(df *dataframe.DataFrame, names []string) (*dataframe.DataFrame, error) {
@@ -0,0 +1,456 @@
+package rw
+
+import (
+	"bufio"
+	"bytes"
+	jsonenc "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	JSONExt   string = ".json"
+	NDJSONExt string = ".ndjson"
+)
+
+// JSON output orientations.
+const (
+	// JSONRecords orients JSON output as an array of records (objects),
+	// one per row.
+	JSONRecords string = "records"
+	// JSONColumns orients JSON output as an object of field arrays.
+	JSONColumns string = "columns"
+)
+
+var (
+	ErrJSONFormat = errors.New("json: input is not an array of records or an object of fields")
+	ErrJSONShape  = errors.New("json: field lengths differ")
+)
+
+// json contains data needed for reading and writing JSON and JSON Lines
+// (NDJSON) files.
+//
+// JSON input is either an array of records (objects), one per row,
+// or a column-oriented object, i.e., an object of field arrays,
+// while NDJSON input contains a single record per line. Field order
+// is the order in which fields first appear in the input, missing
+// values and nulls are read as NaN, and nested values are read as strings.
+type json struct {
+	// File is the file path from which data is read or written to.
+	File string `yaml:"file"`
+	// EnforceExtension determines whether a file name extension will be
+	// enforced on the output file name.
+	EnforceExtension bool `yaml:"enforce_extension"`
+	// KeepStrings determines whether fields containing JSON strings are
+	// kept as strings, instead of having their types inferred from the
+	// string contents, e.g., "1.5" is read as a float unless
+	// KeepStrings is set.
+	KeepStrings bool `yaml:"keep_strings"`
+	// Orient is the JSON output orientation, either 'records' or
+	// 'columns'. It is ignored for NDJSON output.
+	Orient string `yaml:"orient"`
+
+	// lines determines whether the data is in the JSON Lines format.
+	lines bool
+	// raw determines whether all fields are read as unparsed strings.
+	raw bool
+}
+
+func defaultJSON() *json {
+	return &json{
+		Orient: JSONRecords,
+	}
+}
+
+func NewJSON(n *yaml.Node) (*json, error) {
+	return newJSON(n, false)
+}
+
+func NewNDJSON(n *yaml.Node) (*json, error) {
+	return newJSON(n, true)
+}
+
+func newJSON(n *yaml.Node, lines bool) (*json, error) {
+	rw := defaultJSON()
+	rw.lines = lines
+	if err := n.Decode(rw); err != nil {
+		return nil, fmt.Errorf("%v: %w", rw.name(), err)
+	}
+	rw.Orient = strings.ToLower(rw.Orient)
+	if rw.Orient != JSONRecords && rw.Orient != JSONColumns {
+		return nil, fmt.Errorf("%v: %w: %q: %q",
+			rw.name(), common.ErrBadFieldValue, "orient", rw.Orient)
+	}
+	return rw, nil
+}
+
+// name returns the type name used in error messages.
+func (rw *json) name() string {
+	if rw.lines {
+		return "ndjson"
+	}
+	return "json"
+}
+
+func (rw *json) Read() (*dataframe.DataFrame, error) {
+	fn := func(name string) (io.ReadCloser, error) {
+		return os.Open(name)
+	}
+	return rw.ReadFromFn(fn)
+}
+
+func (rw *json) ReadFromFn(fn ReaderFunc) (*dataframe.DataFrame, error) {
+	rc, err := fn(rw.File)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", rw.name(), err)
+	}
+	if rc, err = decompress(rc, rw.File); err != nil {
+		return nil, fmt.Errorf("%v: %w", rw.name(), err)
+	}
+	defer rc.Close()
+	return rw.read(rc)
+}
+
+// jsonTable is a helper for assembling a table from JSON values,
+// where the field order is the order in which the fields first appear.
+type jsonTable struct {
+	names   []string
+	cols    map[string][]string
+	strs    map[string]bool
+	nRows   int
+	lengths map[string]int
+}
+
+func newJSONTable() *jsonTable {
+	return &jsonTable{
+		cols:    make(map[string][]string),
+		strs:    make(map[string]bool),
+		lengths: make(map[string]int),
+	}
+}
+
+// add appends a value to the field name at row i, padding missing
+// values with NaN.
+func (t *jsonTable) add(name string, i int, v any) error {
+	if _, found := t.cols[name]; !found {
+		t.names = append(t.names, name)
+	}
+	col := t.cols[name]
+	for len(col) < i {
+		col = append(col, "NaN")
+	}
+	if len(col) > i {
+		return fmt.Errorf("duplicate field %q", name)
+	}
+	var s string
+	switch x := v.(type) {
+	case nil:
+		s = "NaN"
+	case string:
+		s = x
+		t.strs[name] = true
+	case jsonenc.Number:
+		s = x.String()
+	case bool:
+		s = fmt.Sprint(x)
+	default: // nested arrays and objects
+		b, err := jsonenc.Marshal(x)
+		if err != nil {
+			return err
+		}
+		s = string(b)
+		t.strs[name] = true
+	}
+	t.cols[name] = append(col, s)
+	t.nRows = max(t.nRows, i+1)
+	return nil
+}
+
+// records returns the table as records with a header, padding fields
+// shorter than nRows with NaN.
+func (t *jsonTable) records() [][]string {
+	records := make([][]string, 1, t.nRows+1)
+	records[0] = t.names
+	for i := 0; i < t.nRows; i++ {
+		rec := make([]string, len(t.names))
+		for j, name := range t.names {
+			if col := t.cols[name]; i < len(col) {
+				rec[j] = col[i]
+			} else {
+				rec[j] = "NaN"
+			}
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+// readObject reads a JSON object, whose opening delimiter has already been
+// read, and calls fn for each key-value pair.
+func readObject(dec *jsonenc.Decoder, fn func(key string) error) error {
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := t.(string)
+		if !ok {
+			return ErrJSONFormat
+		}
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+	_, err := dec.Token() // closing delimiter
+	return err
+}
+
+// expectDelim reads the next token and checks whether it is the delimiter d.
+func expectDelim(dec *jsonenc.Decoder, d jsonenc.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if t != d {
+		return ErrJSONFormat
+	}
+	return nil
+}
+
+// readRecord reads a single JSON object as row i of t.
+func readRecord(dec *jsonenc.Decoder, t *jsonTable, i int) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	return readObject(dec, func(key string) error {
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		return t.add(key, i, v)
+	})
+}
+
+func (rw *json) setRaw() { rw.raw = true }
+
+func (rw *json) read(in io.Reader) (*dataframe.DataFrame, error) {
+	t := newJSONTable()
+	dec := jsonenc.NewDecoder(in)
+	dec.UseNumber()
+	var err error
+	if rw.lines {
+		for i := 0; dec.More() && err == nil; i++ {
+			err = readRecord(dec, t, i)
+		}
+	} else {
+		err = rw.readJSON(dec, t)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", rw.name(), err)
+	}
+
+	opts := []dataframe.LoadOption{
+		dataframe.HasHeader(true),
+		dataframe.DefaultType(series.Float),
+	}
+	if rw.KeepStrings {
+		types := make(map[string]series.Type, len(t.strs))
+		for name := range t.strs {
+			types[name] = series.String
+		}
+		opts = append(opts, dataframe.WithTypes(types))
+	}
+	df := dataframe.LoadRecords(t.records(), rawOptions(opts, rw.raw)...)
+	if df.Error() != nil {
+		return nil, fmt.Errorf("%v: %w", rw.name(), df.Error())
+	}
+	return &df, nil
+}
+
+// readJSON reads either an array of records or an object of fields.
+func (rw *json) readJSON(dec *jsonenc.Decoder, t *jsonTable) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case jsonenc.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if err := readRecord(dec, t, i); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+		return err
+	case jsonenc.Delim('{'):
+		err := readObject(dec, func(key string) error {
+			var vals []any
+			if err := dec.Decode(&vals); err != nil {
+				return err
+			}
+			for i, v := range vals {
+				if err := t.add(key, i, v); err != nil {
+					return err
+				}
+			}
+			t.lengths[key] = len(vals)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, name := range t.names {
+			if t.lengths[name] != t.nRows {
+				return fmt.Errorf("%w: %q: %v != %v", ErrJSONShape, name, t.lengths[name], t.nRows)
+			}
+		}
+		return nil
+	}
+	return ErrJSONFormat
+}
+
+// Write writes df to a JSON or NDJSON file, using options from the config.
+// NaN and infinite values, which cannot be represented in JSON,
+// are written as null.
+func (rw *json) Write(df *dataframe.DataFrame) error {
+	if rw.File == "" {
+		return fmt.Errorf("%v: %w: %v", rw.name(), common.ErrUnsetField, "file")
+	}
+	if err := OutDir(rw.File); err != nil {
+		return fmt.Errorf("%v: %w", rw.name(), err)
+	}
+	path := rw.File
+	if rw.EnforceExtension {
+		ext := JSONExt
+		if rw.lines {
+			ext = NDJSONExt
+		}
+		path = SetExt(path, ext)
+	}
+	f, err := create(path)
+	if err != nil {
+		return fmt.Errorf("%v: %w", rw.name(), err)
+	}
+	if err := rw.write(f, df); err != nil {
+		f.Close()
+		return fmt.Errorf("%v: %w", rw.name(), err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("%v: %w", rw.name(), err)
+	}
+	return nil
+}
+
+// jsonValue returns the JSON encoding of a series element.
+func jsonValue(e series.Element) ([]byte, error) {
+	if e.IsNA() {
+		return []byte("null"), nil
+	}
+	switch e.Type() {
+	case series.Float:
+		v := e.Float()
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return []byte("null"), nil
+		}
+		return jsonenc.Marshal(v)
+	case series.Int:
+		v, err := e.Int()
+		if err != nil {
+			return nil, err
+		}
+		return jsonenc.Marshal(v)
+	case series.Bool:
+		v, err := e.Bool()
+		if err != nil {
+			return nil, err
+		}
+		return jsonenc.Marshal(v)
+	}
+	return jsonenc.Marshal(e.String())
+}
+
+// write writes df as JSON to out.
+func (rw *json) write(out io.Writer, df *dataframe.DataFrame) error {
+	w := bufio.NewWriter(out)
+	names := df.Names()
+	keys := make([][]byte, len(names))
+	for i, name := range names {
+		var err error
+		if keys[i], err = jsonenc.Marshal(name); err != nil {
+			return err
+		}
+	}
+	// record writes the i-th row as a JSON object
+	record := func(i int) error {
+		var b bytes.Buffer
+		b.WriteByte('{')
+		for j := range names {
+			v, err := jsonValue(df.Elem(i, j))
+			if err != nil {
+				return err
+			}
+			if j > 0 {
+				b.WriteByte(',')
+			}
+			b.Write(keys[j])
+			b.WriteByte(':')
+			b.Write(v)
+		}
+		b.WriteByte('}')
+		_, err := w.Write(b.Bytes())
+		return err
+	}
+
+	switch {
+	case rw.lines:
+		for i := 0; i < df.Nrow(); i++ {
+			if err := record(i); err != nil {
+				return err
+			}
+			w.WriteByte('\n')
+		}
+	case rw.Orient == JSONColumns:
+		w.WriteByte('{')
+		for j := range names {
+			if j > 0 {
+				w.WriteByte(',')
+			}
+			w.Write(keys[j])
+			w.WriteString(":[")
+			for i := 0; i < df.Nrow(); i++ {
+				v, err := jsonValue(df.Elem(i, j))
+				if err != nil {
+					return err
+				}
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				w.Write(v)
+			}
+			w.WriteByte(']')
+		}
+		w.WriteString("}\n")
+	default:
+		w.WriteByte('[')
+		for i := 0; i < df.Nrow(); i++ {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteByte('\n')
+			if err := record(i); err != nil {
+				return err
+			}
+		}
+		w.WriteString("\n]\n")
+	}
+	return w.Flush()
+}
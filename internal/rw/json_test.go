@@ -0,0 +1,215 @@
+package rw
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type jsonTest struct {
+	Name   string
+	Config string
+	Lines  bool
+	Input  string
+	Output dataframe.DataFrame
+	Error  error
+}
+
+var jsonReadTests = []jsonTest{
+	{
+		Name:   "good-records",
+		Config: ``,
+		Input: `[
+  {"time": 0.1, "p": 1, "ok": true, "name": "a"},
+  {"time": 0.2, "p": 2, "ok": false, "name": "b"}
+]`,
+		Output: dataframe.New(
+			series.New([]float64{0.1, 0.2}, series.Float, "time"),
+			series.New([]int{1, 2}, series.Int, "p"),
+			series.New([]bool{true, false}, series.Bool, "ok"),
+			series.New([]string{"a", "b"}, series.String, "name"),
+		),
+		Error: nil,
+	},
+	{
+		Name:   "good-records-missing",
+		Config: ``,
+		Input:  `[{"b": 1.5, "a": 1}, {"a": 2, "c": null}, {"a": 3, "b": 2.5}]`,
+		Output: dataframe.New(
+			series.New([]float64{1.5, math.NaN(), 2.5}, series.Float, "b"),
+			series.New([]int{1, 2, 3}, series.Int, "a"),
+			series.New([]float64{math.NaN(), math.NaN(), math.NaN()}, series.Float, "c"),
+		),
+		Error: nil,
+	},
+	{
+		Name:   "good-columns",
+		Config: ``,
+		Input:  `{"y": [1.5, 2.5], "x": [1, 2]}`,
+		Output: dataframe.New(
+			series.New([]float64{1.5, 2.5}, series.Float, "y"),
+			series.New([]int{1, 2}, series.Int, "x"),
+		),
+		Error: nil,
+	},
+	{
+		Name:   "good-strings",
+		Config: ``,
+		Input:  `[{"x": "1.5", "v": [1, 2]}]`,
+		Output: dataframe.New(
+			series.New([]float64{1.5}, series.Float, "x"),
+			series.New([]string{"[1,2]"}, series.String, "v"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-keep-strings",
+		Config: `
+keep_strings: true
+`,
+		Input: `[{"x": "1.5", "y": 2.5}]`,
+		Output: dataframe.New(
+			series.New([]string{"1.5"}, series.String, "x"),
+			series.New([]float64{2.5}, series.Float, "y"),
+		),
+		Error: nil,
+	},
+	{
+		Name:   "good-ndjson",
+		Config: ``,
+		Lines:  true,
+		Input: `{"t": 1, "level": "info"}
+
+{"t": 2, "level": "warn", "code": 7}
+`,
+		Output: dataframe.New(
+			series.New([]int{1, 2}, series.Int, "t"),
+			series.New([]string{"info", "warn"}, series.String, "level"),
+			series.New([]interface{}{nil, 7}, series.Int, "code"),
+		),
+		Error: nil,
+	},
+	{
+		Name:   "bad-columns-shape",
+		Config: ``,
+		Input:  `{"x": [1, 2], "y": [1]}`,
+		Output: dataframe.DataFrame{},
+		Error:  ErrJSONShape,
+	},
+	{
+		Name:   "bad-format",
+		Config: ``,
+		Input:  `[1, 2, 3]`,
+		Output: dataframe.DataFrame{},
+		Error:  ErrJSONFormat,
+	},
+}
+
+func TestJSONRead(t *testing.T) {
+	for _, tt := range jsonReadTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			raw, err := io.ReadAll(strings.NewReader(tt.Config))
+			assert.Nil(err, "unexpected io.ReadAll() error")
+			var config yaml.Node
+			err = yaml.Unmarshal(raw, &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+
+			var rw *json
+			if tt.Lines {
+				rw, err = NewNDJSON(&config)
+			} else {
+				rw, err = NewJSON(&config)
+			}
+			assert.Nil(err, "unexpected NewJSON() error")
+			out, err := rw.read(strings.NewReader(tt.Input))
+
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error != nil {
+				assert.Nil(out)
+			} else {
+				assert.Equal(tt.Output.Names(), out.Names())
+				assert.Equal(tt.Output.Types(), out.Types())
+				assert.Equal(tt.Output.Records(), out.Records())
+			}
+		})
+	}
+}
+
+type jsonWriteTest struct {
+	Name   string
+	Config string
+	Lines  bool
+	Input  dataframe.DataFrame
+	Output string
+}
+
+var jsonWriteInput = dataframe.New(
+	series.New([]float64{0.5, math.NaN()}, series.Float, "x"),
+	series.New([]int{1, 2}, series.Int, "n"),
+	series.New([]string{"a", "b"}, series.String, "s"),
+)
+
+var jsonWriteTests = []jsonWriteTest{
+	{
+		Name:   "good-records",
+		Config: ``,
+		Input:  jsonWriteInput,
+		Output: `[
+{"x":0.5,"n":1,"s":"a"},
+{"x":null,"n":2,"s":"b"}
+]
+`,
+	},
+	{
+		Name: "good-columns",
+		Config: `
+orient: columns
+`,
+		Input: jsonWriteInput,
+		Output: `{"x":[0.5,null],"n":[1,2],"s":["a","b"]}
+`,
+	},
+	{
+		Name:   "good-ndjson",
+		Config: ``,
+		Lines:  true,
+		Input:  jsonWriteInput,
+		Output: `{"x":0.5,"n":1,"s":"a"}
+{"x":null,"n":2,"s":"b"}
+`,
+	},
+}
+
+func TestJSONWrite(t *testing.T) {
+	for _, tt := range jsonWriteTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var config yaml.Node
+			err := yaml.Unmarshal([]byte(tt.Config), &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+
+			var rw *json
+			if tt.Lines {
+				rw, err = NewNDJSON(&config)
+			} else {
+				rw, err = NewJSON(&config)
+			}
+			assert.Nil(err, "unexpected NewJSON() error")
+
+			var b bytes.Buffer
+			err = rw.write(&b, &tt.Input)
+			assert.Nil(err)
+			assert.Equal(tt.Output, b.String())
+		})
+	}
+}
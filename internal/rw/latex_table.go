@@ -0,0 +1,113 @@
+package rw
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	LaTeXExt string = ".tex"
+)
+
+var (
+	latexReplacer = strings.NewReplacer(
+		`\`, `\textbackslash{}`,
+		`&`, `\&`,
+		`%`, `\%`,
+		`$`, `\$`,
+		`#`, `\#`,
+		`_`, `\_`,
+		`{`, `\{`,
+		`}`, `\}`,
+		`~`, `\textasciitilde{}`,
+		`^`, `\textasciicircum{}`,
+	)
+)
+
+// latexTable writes a LaTeX booktabs 'tabular' environment.
+type latexTable struct {
+	// Siunitx determines whether numeric columns are siunitx 'S' columns
+	// by default, instead of right-aligned columns.
+	Siunitx bool `yaml:"siunitx"`
+
+	tableSpec `yaml:",inline"`
+}
+
+func defaultLaTeXTable() *latexTable {
+	return &latexTable{
+		tableSpec: defaultTableSpec(),
+	}
+}
+
+func NewLaTeXTable(n *yaml.Node) (*latexTable, error) {
+	rw := defaultLaTeXTable()
+	if err := n.Decode(rw); err != nil {
+		return nil, fmt.Errorf("latex-table: %w", err)
+	}
+	if err := rw.validate("lcrS"); err != nil {
+		return nil, fmt.Errorf("latex-table: %w", err)
+	}
+	return rw, nil
+}
+
+// Write writes df to a LaTeX table file, using options from the config.
+func (rw *latexTable) Write(df *dataframe.DataFrame) error {
+	err := rw.writeTable(LaTeXExt, func(out io.Writer) error {
+		return rw.write(out, df)
+	})
+	if err != nil {
+		return fmt.Errorf("latex-table: %w", err)
+	}
+	return nil
+}
+
+// write writes the table. Header labels set in the config are written
+// as raw TeX, while field names used as labels are escaped, and all labels
+// are braced in 'S' columns. Non-numeric values, and NaN and infinity
+// spellings of numeric values, are escaped.
+func (rw *latexTable) write(out io.Writer, df *dataframe.DataFrame) error {
+	numeric := byte('r')
+	if rw.Siunitx {
+		numeric = 'S'
+	}
+	labels, align, records, err := rw.table(df, numeric)
+	if err != nil {
+		return err
+	}
+	numbers := make([]bool, len(labels))
+	for j, t := range df.Types() {
+		numbers[j] = t == series.Float || t == series.Int
+	}
+	for j, name := range df.Names() {
+		if _, found := rw.Labels[name]; !found {
+			labels[j] = latexReplacer.Replace(labels[j])
+		}
+		if align[j] == 'S' {
+			labels[j] = "{" + labels[j] + "}"
+		}
+	}
+	special := []string{rw.NaN, rw.Inf, rw.negInf()}
+	w := bufio.NewWriter(out)
+	w.WriteString(`\begin{tabular}{` + string(align) + "}\n")
+	w.WriteString("\\toprule\n")
+	w.WriteString(strings.Join(labels, " & ") + ` \\` + "\n")
+	w.WriteString("\\midrule\n")
+	for _, r := range records {
+		for j := range r {
+			if !numbers[j] || slices.Contains(special, r[j]) {
+				r[j] = latexReplacer.Replace(r[j])
+			}
+		}
+		w.WriteString(strings.Join(r, " & ") + ` \\` + "\n")
+	}
+	w.WriteString("\\bottomrule\n")
+	w.WriteString("\\end{tabular}\n")
+	return w.Flush()
+}
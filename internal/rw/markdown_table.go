@@ -0,0 +1,109 @@
+package rw
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-gota/gota/dataframe"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	MarkdownExt string = ".md"
+)
+
+// markdownTable writes a GitHub Flavored Markdown table.
+type markdownTable struct {
+	tableSpec `yaml:",inline"`
+}
+
+func defaultMarkdownTable() *markdownTable {
+	return &markdownTable{
+		tableSpec: defaultTableSpec(),
+	}
+}
+
+func NewMarkdownTable(n *yaml.Node) (*markdownTable, error) {
+	rw := defaultMarkdownTable()
+	if err := n.Decode(rw); err != nil {
+		return nil, fmt.Errorf("markdown-table: %w", err)
+	}
+	if err := rw.validate("lcr"); err != nil {
+		return nil, fmt.Errorf("markdown-table: %w", err)
+	}
+	return rw, nil
+}
+
+// Write writes df to a Markdown table file, using options from the config.
+func (rw *markdownTable) Write(df *dataframe.DataFrame) error {
+	err := rw.writeTable(MarkdownExt, func(out io.Writer) error {
+		return rw.write(out, df)
+	})
+	if err != nil {
+		return fmt.Errorf("markdown-table: %w", err)
+	}
+	return nil
+}
+
+// markdownEscape escapes characters of v which would break the table.
+func markdownEscape(v string) string {
+	v = strings.ReplaceAll(v, "|", `\|`)
+	return strings.ReplaceAll(v, "\n", " ")
+}
+
+func (rw *markdownTable) write(out io.Writer, df *dataframe.DataFrame) error {
+	labels, align, records, err := rw.table(df, 'r')
+	if err != nil {
+		return err
+	}
+	rows := append([][]string{labels}, records...)
+	widths := make([]int, len(labels))
+	for _, r := range rows {
+		for j := range r {
+			r[j] = markdownEscape(r[j])
+			widths[j] = max(widths[j], utf8.RuneCountInString(r[j]), 3)
+		}
+	}
+	pad := func(v string, j int) string {
+		n := widths[j] - utf8.RuneCountInString(v)
+		switch align[j] {
+		case 'r':
+			return strings.Repeat(" ", n) + v
+		case 'c':
+			return strings.Repeat(" ", n/2) + v + strings.Repeat(" ", n-n/2)
+		}
+		return v + strings.Repeat(" ", n)
+	}
+	w := bufio.NewWriter(out)
+	writeRow := func(r []string) {
+		for j, v := range r {
+			w.WriteString("| ")
+			w.WriteString(pad(v, j))
+			w.WriteString(" ")
+		}
+		w.WriteString("|\n")
+	}
+	writeRow(rows[0])
+	for j, a := range align {
+		rule := strings.Repeat("-", widths[j])
+		switch a {
+		case 'l':
+			rule = ":" + rule[1:]
+		case 'c':
+			rule = ":" + rule[2:] + ":"
+		case 'r':
+			rule = rule[1:] + ":"
+		}
+		w.WriteString("| ")
+		w.WriteString(rule)
+		w.WriteString(" ")
+	}
+	w.WriteString("|\n")
+	for _, r := range rows[1:] {
+		writeRow(r)
+	}
+	return w.Flush()
+}
@@ -3,18 +3,51 @@ package rw
 import (
 	"errors"
 	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/Milover/post/internal/common"
 	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
 	"gopkg.in/yaml.v3"
 )
 
 var (
 	ErrMultipleEmpty = errors.New("multple: no inputs defined")
+	ErrMultipleKey   = errors.New("multiple: key field not found")
+)
+
+// Join modes.
+const (
+	// JoinInner keeps only rows whose keys are found in all inputs.
+	JoinInner string = "inner"
+	// JoinLeft keeps all rows of the first input.
+	JoinLeft string = "left"
+	// JoinOuter keeps all rows of all inputs.
+	JoinOuter string = "outer"
 )
 
 type multiple struct {
 	// FormatSpecs is a list of configs for specifying each input type.
 	FormatSpecs []Config `yaml:"format_specs"`
+	// Join is the join mode, one of 'inner', 'left' or 'outer'.
+	// If left empty, inputs are joined by row position, in which case all
+	// inputs must have the same number of rows.
+	Join string `yaml:"join"`
+	// Keys are the names of the fields on which inputs are joined.
+	Keys []string `yaml:"keys"`
+	// Tolerance is the absolute tolerance used when comparing numeric keys.
+	// If it is greater than 0, each row is matched with at most one row,
+	// the nearest one, i.e., the one with the smallest sum of absolute
+	// numeric key differences, where ties are broken by row order.
+	Tolerance float64 `yaml:"tolerance"`
+	// Suffixes are the suffixes appended to the names of fields which are
+	// present in several inputs, one per input.
+	// If left empty, the suffix of the i-th input is '_<i>'.
+	Suffixes []string `yaml:"suffixes"`
 }
 
 func defaultMultiple() *multiple {
@@ -29,25 +62,317 @@ func NewMultiple(n *yaml.Node) (*multiple, error) {
 	if len(rw.FormatSpecs) == 0 {
 		return nil, ErrMultipleEmpty
 	}
+	rw.Join = strings.ToLower(rw.Join)
+	switch rw.Join {
+	case "":
+	case JoinInner, JoinLeft, JoinOuter:
+		if len(rw.Keys) == 0 {
+			return nil, fmt.Errorf("multiple: %w: %q", common.ErrUnsetField, "keys")
+		}
+	default:
+		return nil, fmt.Errorf("multiple: %w: %q: %q", common.ErrBadFieldValue, "join", rw.Join)
+	}
+	if len(rw.Suffixes) > 0 && len(rw.Suffixes) != len(rw.FormatSpecs) {
+		return nil, fmt.Errorf("multiple: %w: %q: need %v suffixes, got %v",
+			common.ErrBadFieldValue, "suffixes", len(rw.FormatSpecs), len(rw.Suffixes))
+	}
+	if rw.Tolerance < 0 {
+		return nil, fmt.Errorf("multiple: %w: %q: %v", common.ErrBadFieldValue, "tolerance", rw.Tolerance)
+	}
 	return rw, nil
 }
 
 func (rw *multiple) Read() (*dataframe.DataFrame, error) {
-	var df *dataframe.DataFrame
+	dfs := make([]*dataframe.DataFrame, len(rw.FormatSpecs))
 	for i := range rw.FormatSpecs {
 		temp, err := Read(&rw.FormatSpecs[i])
 		if err != nil {
 			return nil, fmt.Errorf("multiple: %w", err)
 		}
+		dfs[i] = temp
+	}
+	if err := rw.rename(dfs); err != nil {
+		return nil, fmt.Errorf("multiple: %w", err)
+	}
+	var df *dataframe.DataFrame
+	for _, temp := range dfs {
 		// concatonate the new dataframe
 		if df == nil {
 			df = temp
-		} else {
+			continue
+		}
+		if rw.Join == "" {
 			*df = df.CBind(*temp)
-			if df.Error() != nil {
-				return nil, fmt.Errorf("multiple: %w", df.Error())
-			}
+		} else {
+			*df = rw.join(df, temp)
+		}
+		if df.Error() != nil {
+			return nil, fmt.Errorf("multiple: %w", df.Error())
+		}
+	}
+	if df != nil && rw.Join == JoinOuter {
+		order := make([]dataframe.Order, len(rw.Keys))
+		for i, k := range rw.Keys {
+			order[i] = dataframe.Sort(k)
+		}
+		*df = df.Arrange(order...)
+		if df.Error() != nil {
+			return nil, fmt.Errorf("multiple: %w", df.Error())
 		}
 	}
 	return df, nil
 }
+
+// suffix returns the suffix of the i-th input.
+func (rw *multiple) suffix(i int) string {
+	if len(rw.Suffixes) > 0 {
+		return rw.Suffixes[i]
+	}
+	return "_" + strconv.Itoa(i)
+}
+
+// rename checks whether all inputs contain the keys, and appends
+// a suffix to the names of (non-key) fields present in several inputs.
+// If neither join nor suffixes are set, fields are not renamed, i.e.,
+// duplicate names are numbered in order by dataframe.DataFrame.CBind.
+func (rw *multiple) rename(dfs []*dataframe.DataFrame) error {
+	if rw.Join == "" && len(rw.Suffixes) == 0 {
+		return nil
+	}
+	if rw.Join != "" {
+		for i, df := range dfs {
+			for _, k := range rw.Keys {
+				if !slices.Contains(df.Names(), k) {
+					return fmt.Errorf("%w: %q in input %v", ErrMultipleKey, k, i)
+				}
+			}
+		}
+	}
+	count := make(map[string]int)
+	for _, df := range dfs {
+		for _, name := range df.Names() {
+			count[name]++
+		}
+	}
+	for i, df := range dfs {
+		names := df.Names()
+		renamed := false
+		for j, name := range names {
+			if count[name] > 1 && !(rw.Join != "" && slices.Contains(rw.Keys, name)) {
+				names[j] = name + rw.suffix(i)
+				renamed = true
+			}
+		}
+		if renamed {
+			if err := df.SetNames(names...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// joinKeys holds the key values of each row of a dataframe.DataFrame,
+// numeric keys as floats and others as strings.
+type joinKeys struct {
+	num [][]float64
+	str [][]string
+}
+
+// newJoinKeys extracts the key values of df, where numeric determines
+// which keys are numeric.
+func newJoinKeys(df *dataframe.DataFrame, keys []string, numeric []bool) joinKeys {
+	var k joinKeys
+	for i, key := range keys {
+		s := df.Col(key)
+		if numeric[i] {
+			k.num = append(k.num, s.Float())
+			k.str = append(k.str, nil)
+		} else {
+			k.num = append(k.num, nil)
+			k.str = append(k.str, s.Records())
+		}
+	}
+	return k
+}
+
+// cmp compares the k-th key of row i of a and row j of b,
+// with tolerance tol for numeric keys.
+func (a joinKeys) cmp(i int, b joinKeys, j, k int, tol float64) int {
+	if a.num[k] == nil {
+		return strings.Compare(a.str[k][i], b.str[k][j])
+	}
+	x, y := a.num[k][i], b.num[k][j]
+	switch {
+	case math.Abs(x-y) <= tol:
+		return 0
+	case x < y:
+		return -1
+	}
+	return 1
+}
+
+// dist returns the sum of the absolute differences of the numeric keys
+// of row i of a and row j of b.
+func (a joinKeys) dist(i int, b joinKeys, j int) float64 {
+	d := 0.0
+	for k := range a.num {
+		if a.num[k] != nil {
+			d += math.Abs(a.num[k][i] - b.num[k][j])
+		}
+	}
+	return d
+}
+
+// isNumeric reports whether s holds numeric values.
+func isNumeric(s series.Series) bool {
+	return s.Type() == series.Float || s.Type() == series.Int
+}
+
+// join joins right to left on the keys.
+func (rw *multiple) join(left, right *dataframe.DataFrame) dataframe.DataFrame {
+	numeric := make([]bool, len(rw.Keys))
+	for i, k := range rw.Keys {
+		numeric[i] = isNumeric(left.Col(k)) && isNumeric(right.Col(k))
+	}
+	lk := newJoinKeys(left, rw.Keys, numeric)
+	rk := newJoinKeys(right, rw.Keys, numeric)
+	hasNaN := func(k joinKeys, i int) bool {
+		for _, v := range k.num {
+			if v != nil && math.IsNaN(v[i]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// sort the right rows by key, so matches can be found by binary search;
+	// rows with NaN keys never match, and would break the ordering
+	idx := make([]int, 0, right.Nrow())
+	for i := 0; i < right.Nrow(); i++ {
+		if !hasNaN(rk, i) {
+			idx = append(idx, i)
+		}
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		for k := range rw.Keys {
+			if c := rk.cmp(idx[a], rk, idx[b], k, 0); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	// candidate pairs of matching left and right rows
+	type pair struct {
+		l, r int
+		d    float64
+	}
+	var pairs []pair
+	for i := 0; i < left.Nrow(); i++ {
+		if hasNaN(lk, i) {
+			continue
+		}
+		start := sort.Search(len(idx), func(j int) bool {
+			return rk.cmp(idx[j], lk, i, 0, rw.Tolerance) >= 0
+		})
+		for j := start; j < len(idx) && rk.cmp(idx[j], lk, i, 0, rw.Tolerance) == 0; j++ {
+			r := idx[j]
+			match := true
+			for k := 1; k < len(rw.Keys) && match; k++ {
+				match = lk.cmp(i, rk, r, k, rw.Tolerance) == 0
+			}
+			if match {
+				pairs = append(pairs, pair{i, r, lk.dist(i, rk, r)})
+			}
+		}
+	}
+	if rw.Tolerance > 0 {
+		// match each row at most once, nearest pairs first
+		sort.SliceStable(pairs, func(a, b int) bool {
+			pa, pb := pairs[a], pairs[b]
+			if pa.d != pb.d {
+				return pa.d < pb.d
+			}
+			if pa.l != pb.l {
+				return pa.l < pb.l
+			}
+			return pa.r < pb.r
+		})
+		usedL, usedR := make(map[int]bool), make(map[int]bool)
+		kept := pairs[:0]
+		for _, p := range pairs {
+			if !usedL[p.l] && !usedR[p.r] {
+				usedL[p.l], usedR[p.r] = true, true
+				kept = append(kept, p)
+			}
+		}
+		pairs = kept
+		sort.SliceStable(pairs, func(a, b int) bool {
+			return pairs[a].l < pairs[b].l
+		})
+	}
+
+	// pairs of matching left and right rows, -1 denotes no match
+	var lRows, rRows []int
+	matched := make([]bool, right.Nrow())
+	for i, p := 0, 0; i < left.Nrow(); i++ {
+		found := false
+		for ; p < len(pairs) && pairs[p].l == i; p++ {
+			lRows, rRows = append(lRows, i), append(rRows, pairs[p].r)
+			matched[pairs[p].r] = true
+			found = true
+		}
+		if !found && rw.Join != JoinInner {
+			lRows, rRows = append(lRows, i), append(rRows, -1)
+		}
+	}
+	if rw.Join == JoinOuter {
+		for r := range matched {
+			if !matched[r] {
+				lRows, rRows = append(lRows, -1), append(rRows, r)
+			}
+		}
+	}
+
+	// assemble the output, keys first
+	column := func(s series.Series, rows []int, typ series.Type) series.Series {
+		vals := make([]interface{}, len(rows))
+		for i, r := range rows {
+			if r >= 0 {
+				vals[i] = s.Elem(r).Val()
+			}
+		}
+		return series.New(vals, typ, s.Name)
+	}
+	ss := make([]series.Series, 0, left.Ncol()+right.Ncol())
+	for i, k := range rw.Keys {
+		l, r := left.Col(k), right.Col(k)
+		typ := l.Type()
+		if numeric[i] && r.Type() == series.Float {
+			typ = series.Float
+		}
+		vals := make([]interface{}, len(lRows))
+		for j := range lRows {
+			if lRows[j] >= 0 {
+				vals[j] = l.Elem(lRows[j]).Val()
+			} else {
+				vals[j] = r.Elem(rRows[j]).Val()
+			}
+		}
+		ss = append(ss, series.New(vals, typ, k))
+	}
+	for _, name := range left.Names() {
+		if !slices.Contains(rw.Keys, name) {
+			s := left.Col(name)
+			ss = append(ss, column(s, lRows, s.Type()))
+		}
+	}
+	for _, name := range right.Names() {
+		if !slices.Contains(rw.Keys, name) {
+			s := right.Col(name)
+			ss = append(ss, column(s, rRows, s.Type()))
+		}
+	}
+	return dataframe.New(ss...)
+}
@@ -2,6 +2,7 @@ package rw
 
 import (
 	"io"
+	"math"
 	"strings"
 	"testing"
 
@@ -19,6 +20,33 @@ type multipleTest struct {
 }
 
 var multipleReadTests = []multipleTest{
+	{
+		Name: "good-duplicate-names",
+		Config: `
+format_specs:
+  - type: csv
+    fields: [x, a]
+    type_spec:
+      file: 'testdata/foam_series.good/0.1/data.csv'
+  - type: csv
+    fields: [b, c]
+    type_spec:
+      file: 'testdata/foam_series.good/0.1/data.csv'
+  - type: csv
+    fields: [x, d]
+    type_spec:
+      file: 'testdata/foam_series.good/0.1/data.csv'
+`,
+		Output: dataframe.New(
+			series.New([]int{0, 1, 2, 3, 4, 5}, series.Int, "x_0"),
+			series.New([]int{0, 1, 2, 2, 1, 0}, series.Int, "a"),
+			series.New([]int{0, 1, 2, 3, 4, 5}, series.Int, "b"),
+			series.New([]int{0, 1, 2, 2, 1, 0}, series.Int, "c"),
+			series.New([]int{0, 1, 2, 3, 4, 5}, series.Int, "x_1"),
+			series.New([]int{0, 1, 2, 2, 1, 0}, series.Int, "d"),
+		),
+		Error: nil,
+	},
 	{
 		Name: "good-dat",
 		Config: `
@@ -117,6 +145,110 @@ format_specs:
 		),
 		Error: nil,
 	},
+	{
+		Name: "good-join-inner-tolerance",
+		Config: `
+join: inner
+keys: [time]
+tolerance: 1e-6
+format_specs:
+  - type: csv
+    type_spec:
+      file: 'testdata/multiple_join/forces.csv'
+  - type: csv
+    type_spec:
+      file: 'testdata/multiple_join/probes.csv'
+`,
+		Output: dataframe.New(
+			series.New([]float64{0.1, 0.3}, series.Float, "time"),
+			series.New([]int{1, 3}, series.Int, "fx_0"),
+			series.New([]int{10, 30}, series.Int, "p"),
+			series.New([]int{7, 9}, series.Int, "fx_1"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-join-inner-exact",
+		Config: `
+join: inner
+keys: [time]
+suffixes: [_forces, _probes]
+format_specs:
+  - type: csv
+    type_spec:
+      file: 'testdata/multiple_join/forces.csv'
+  - type: csv
+    type_spec:
+      file: 'testdata/multiple_join/probes.csv'
+`,
+		Output: dataframe.New(
+			series.New([]float64{0.3}, series.Float, "time"),
+			series.New([]int{3}, series.Int, "fx_forces"),
+			series.New([]int{30}, series.Int, "p"),
+			series.New([]int{9}, series.Int, "fx_probes"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-join-left",
+		Config: `
+join: left
+keys: [time]
+tolerance: 1e-6
+format_specs:
+  - type: csv
+    type_spec:
+      file: 'testdata/multiple_join/forces.csv'
+  - type: csv
+    type_spec:
+      file: 'testdata/multiple_join/probes.csv'
+`,
+		Output: dataframe.New(
+			series.New([]float64{0.1, 0.2, 0.3}, series.Float, "time"),
+			series.New([]int{1, 2, 3}, series.Int, "fx_0"),
+			series.New([]interface{}{10, nil, 30}, series.Int, "p"),
+			series.New([]interface{}{7, nil, 9}, series.Int, "fx_1"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-join-outer",
+		Config: `
+join: outer
+keys: [time]
+tolerance: 1e-6
+format_specs:
+  - type: csv
+    type_spec:
+      file: 'testdata/multiple_join/probes.csv'
+  - type: csv
+    type_spec:
+      file: 'testdata/multiple_join/forces.csv'
+`,
+		Output: dataframe.New(
+			series.New([]float64{0.1000001, 0.2, 0.3, 0.4}, series.Float, "time"),
+			series.New([]interface{}{10, nil, 30, 40}, series.Int, "p"),
+			series.New([]interface{}{7, nil, 9, 10}, series.Int, "fx_0"),
+			series.New([]interface{}{1, 2, 3, nil}, series.Int, "fx_1"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "bad-join-key",
+		Config: `
+join: inner
+keys: [x]
+format_specs:
+  - type: csv
+    type_spec:
+      file: 'testdata/multiple_join/forces.csv'
+  - type: csv
+    type_spec:
+      file: 'testdata/multiple_join/probes.csv'
+`,
+		Output: dataframe.DataFrame{},
+		Error:  ErrMultipleKey,
+	},
 }
 
 func TestMultipleRead(t *testing.T) {
@@ -143,3 +275,98 @@ func TestMultipleRead(t *testing.T) {
 		})
 	}
 }
+
+func TestMultipleJoin(t *testing.T) {
+	nan := math.NaN()
+	for _, tt := range []struct {
+		Name      string
+		Join      string
+		Tolerance float64
+		Left      []float64
+		Right     []float64
+		Output    dataframe.DataFrame
+	}{
+		{
+			Name:  "good-nan-keys",
+			Join:  JoinInner,
+			Left:  []float64{0.1, 0.2, nan},
+			Right: []float64{nan, 0.2, nan, 0.1, 0.3},
+			Output: dataframe.New(
+				series.New([]float64{0.1, 0.2}, series.Float, "time"),
+				series.New([]int{0, 1}, series.Int, "l"),
+				series.New([]int{3, 1}, series.Int, "r"),
+			),
+		},
+		{
+			Name:  "good-nan-keys-outer",
+			Join:  JoinOuter,
+			Left:  []float64{0.1, nan},
+			Right: []float64{nan, 0.1},
+			Output: dataframe.New(
+				series.New([]float64{0.1, nan, nan}, series.Float, "time"),
+				series.New([]interface{}{0, 1, nil}, series.Int, "l"),
+				series.New([]interface{}{1, nil, 0}, series.Int, "r"),
+			),
+		},
+		{
+			Name:      "good-tolerance-nearest",
+			Join:      JoinLeft,
+			Tolerance: 1e-3,
+			Left:      []float64{0.1, 0.1004},
+			Right:     []float64{0.1002, 0.1003, 0.5},
+			Output: dataframe.New(
+				series.New([]float64{0.1, 0.1004}, series.Float, "time"),
+				series.New([]int{0, 1}, series.Int, "l"),
+				series.New([]int{0, 1}, series.Int, "r"),
+			),
+		},
+		{
+			Name:      "good-tolerance-once",
+			Join:      JoinLeft,
+			Tolerance: 1e-3,
+			Left:      []float64{0.1, 0.1001, 0.1002},
+			Right:     []float64{0.1001},
+			Output: dataframe.New(
+				series.New([]float64{0.1, 0.1001, 0.1002}, series.Float, "time"),
+				series.New([]int{0, 1, 2}, series.Int, "l"),
+				series.New([]interface{}{nil, 0, nil}, series.Int, "r"),
+			),
+		},
+		{
+			Name:      "good-tolerance-ties",
+			Join:      JoinInner,
+			Tolerance: 0.5,
+			Left:      []float64{1},
+			Right:     []float64{1.25, 0.75},
+			Output: dataframe.New(
+				series.New([]float64{1}, series.Float, "time"),
+				series.New([]int{0}, series.Int, "l"),
+				series.New([]int{0}, series.Int, "r"),
+			),
+		},
+	} {
+		t.Run(tt.Name, func(t *testing.T) {
+			ids := func(n int) []int {
+				id := make([]int, n)
+				for i := range id {
+					id[i] = i
+				}
+				return id
+			}
+			left := dataframe.New(
+				series.New(tt.Left, series.Float, "time"),
+				series.New(ids(len(tt.Left)), series.Int, "l"),
+			)
+			right := dataframe.New(
+				series.New(tt.Right, series.Float, "time"),
+				series.New(ids(len(tt.Right)), series.Int, "r"),
+			)
+			rw := defaultMultiple()
+			rw.Join = tt.Join
+			rw.Keys = []string{"time"}
+			rw.Tolerance = tt.Tolerance
+			out := rw.join(&left, &right)
+			assert.Equal(t, tt.Output.Records(), out.Records())
+		})
+	}
+}
@@ -0,0 +1,343 @@
+package rw
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+)
+
+// Default number separators and spellings.
+const (
+	DecimalSeparator   string = "."
+	ThousandsSeparator string = ""
+	NaNSpelling        string = "NaN"
+	InfSpelling        string = "+Inf"
+)
+
+var (
+	// floatFormatRegexp matches a single fmt floating point verb, with
+	// optional flags, width and precision, e.g., '%g', '%.6e' or '%10.3f'.
+	floatFormatRegexp = regexp.MustCompile(`^%([-+ #0]*\d*)(\.\d+)?([eEfFgG])$`)
+)
+
+// numberSpec defines how numeric values are written, e.g., with a decimal
+// comma, as is common in some locales, or with a fixed precision.
+type numberSpec struct {
+	// DecimalSeparator separates the integer and fractional parts of
+	// a number, '.' by default.
+	DecimalSeparator string `yaml:"decimal_separator"`
+	// ThousandsSeparator separates groups of thousands of the integer
+	// part of a number, none by default.
+	ThousandsSeparator string `yaml:"thousands_separator"`
+	// FloatFormat is the fmt format used for writing floating point
+	// values, e.g., '%g', '%.6e' or '%.3f'. If unset, the default format
+	// of the output type is used.
+	FloatFormat string `yaml:"float_format"`
+	// Precision maps field names to the precision used for writing
+	// the field, overriding the precision of FloatFormat.
+	Precision map[string]int `yaml:"precision"`
+	// NaN is the spelling of NaN and missing values, 'NaN' by default.
+	NaN string `yaml:"nan"`
+	// Inf is the spelling of positive infinity, '+Inf' by default.
+	// Negative infinity is spelled as Inf prefixed by '-', with
+	// a leading '+' removed.
+	Inf string `yaml:"inf"`
+}
+
+func defaultNumberSpec() numberSpec {
+	return numberSpec{
+		DecimalSeparator:   DecimalSeparator,
+		ThousandsSeparator: ThousandsSeparator,
+		NaN:                NaNSpelling,
+		Inf:                InfSpelling,
+	}
+}
+
+// validate checks whether the separators are valid, i.e., whether the
+// decimal separator is a single character, and the thousands separator is
+// at most a single character, distinct from the decimal separator,
+// and neither is a digit. It also checks whether the float format and
+// precisions are valid.
+func (n numberSpec) validate() error {
+	if utf8.RuneCountInString(n.DecimalSeparator) != 1 ||
+		strings.ContainsAny(n.DecimalSeparator, "0123456789+-") {
+		return fmt.Errorf("%w: %q: %q",
+			common.ErrBadFieldValue, "decimal_separator", n.DecimalSeparator)
+	}
+	if utf8.RuneCountInString(n.ThousandsSeparator) > 1 ||
+		strings.ContainsAny(n.ThousandsSeparator, "0123456789+-") ||
+		n.ThousandsSeparator == n.DecimalSeparator {
+		return fmt.Errorf("%w: %q: %q",
+			common.ErrBadFieldValue, "thousands_separator", n.ThousandsSeparator)
+	}
+	if n.FloatFormat != "" && !floatFormatRegexp.MatchString(n.FloatFormat) {
+		return fmt.Errorf("%w: %q: %q",
+			common.ErrBadFieldValue, "float_format", n.FloatFormat)
+	}
+	for name, p := range n.Precision {
+		if p < 0 {
+			return fmt.Errorf("%w: %q: %q: %v",
+				common.ErrBadFieldValue, "precision", name, p)
+		}
+	}
+	return nil
+}
+
+// isDefault reports whether the default separators and spellings are used.
+func (n numberSpec) isDefault() bool {
+	return n.DecimalSeparator == DecimalSeparator &&
+		n.ThousandsSeparator == ThousandsSeparator &&
+		n.NaN == NaNSpelling &&
+		n.Inf == InfSpelling
+}
+
+// negInf returns the spelling of negative infinity.
+func (n numberSpec) negInf() string {
+	return "-" + strings.TrimPrefix(n.Inf, "+")
+}
+
+// parse converts a numeric value v, written using the separators and
+// spellings, to a value which can be parsed by strconv, and reports
+// whether v is numeric. Thousands separators are removed only if the
+// integer part is correctly grouped, e.g., '1.234.567', so that values
+// such as dates, e.g., '12.05.2023', or versions, e.g., '1.5', are not
+// read as numbers. Values which are not numeric are returned unchanged.
+func (n numberSpec) parse(v string) (string, bool) {
+	switch v {
+	case n.NaN:
+		return NaNSpelling, true
+	case n.Inf:
+		return InfSpelling, true
+	case n.negInf():
+		return "-Inf", true
+	}
+	integer, fraction, found := strings.Cut(v, n.DecimalSeparator)
+	if n.ThousandsSeparator != "" {
+		if strings.Contains(fraction, n.ThousandsSeparator) {
+			return v, false
+		}
+		if strings.Contains(integer, n.ThousandsSeparator) {
+			var ok bool
+			if integer, ok = n.ungroup(integer); !ok {
+				return v, false
+			}
+		}
+	}
+	// a '.' is not a decimal separator, unless it is defined as one
+	if n.DecimalSeparator != "." && strings.Contains(integer+fraction, ".") {
+		return v, false
+	}
+	s := integer
+	if found {
+		s += "." + fraction
+	}
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return v, false
+	}
+	return s, true
+}
+
+// ungroup removes the thousands separators from the integer part of
+// a number, and reports whether it is correctly grouped, i.e., whether it
+// consists of an optional sign, 1-3 digits and groups of exactly 3 digits.
+func (n numberSpec) ungroup(integer string) (string, bool) {
+	sign := ""
+	if len(integer) > 0 && (integer[0] == '-' || integer[0] == '+') {
+		sign, integer = integer[:1], integer[1:]
+	}
+	groups := strings.Split(integer, n.ThousandsSeparator)
+	for i, g := range groups {
+		if !isDigits(g) || len(g) == 0 || len(g) > 3 || (i > 0 && len(g) != 3) {
+			return "", false
+		}
+	}
+	return sign + strings.Join(groups, ""), true
+}
+
+// isMissingRecord reports whether v denotes a missing value
+// when loading records.
+func isMissingRecord(v string) bool {
+	return v == "" || v == "NA" || v == "NaN" || v == "<nil>"
+}
+
+// parseRecords converts all numeric values of records, except the
+// header if header is set, using parse, and reports for each column
+// whether all of its (non-missing) values are numeric. Values of columns
+// which are not numeric are kept unchanged, unless raw is set, i.e., if
+// the field types are defined by a schema, in which case all numeric
+// values are converted. If the default separators and spellings are
+// used, records are kept unchanged and nil is returned.
+func (n numberSpec) parseRecords(records [][]string, header, raw bool) []bool {
+	if n.isDefault() {
+		return nil
+	}
+	if header && len(records) > 0 {
+		records = records[1:]
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	numeric := make([]bool, len(records[0]))
+	for j := range numeric {
+		numeric[j] = true
+		for _, r := range records {
+			if j >= len(r) || isMissingRecord(r[j]) {
+				continue
+			}
+			if _, ok := n.parse(r[j]); !ok {
+				numeric[j] = false
+				break
+			}
+		}
+	}
+	for _, r := range records {
+		for j := range r {
+			if j < len(numeric) && !numeric[j] && !raw {
+				continue
+			}
+			r[j], _ = n.parse(r[j])
+		}
+	}
+	return numeric
+}
+
+// loadRecords converts the numeric values of records using parseRecords,
+// and loads them into a dataframe.DataFrame using opts. Unless raw is set,
+// columns which are not numeric are loaded as strings, regardless of
+// the detected type, e.g., a column of versions, such as '1.5', when
+// the decimal separator is ','.
+func (n numberSpec) loadRecords(records [][]string, header, raw bool, opts []dataframe.LoadOption) dataframe.DataFrame {
+	numeric := n.parseRecords(records, header, raw)
+	df := dataframe.LoadRecords(records, rawOptions(opts, raw)...)
+	if raw || df.Error() != nil {
+		return df
+	}
+	if header {
+		records = records[1:]
+	}
+	names := df.Names()
+	for j, ok := range numeric {
+		if ok || j >= len(names) || df.Col(names[j]).Type() == series.String {
+			continue
+		}
+		vals := make([]string, len(records))
+		for i, r := range records {
+			vals[i] = r[j]
+		}
+		df = df.Mutate(series.New(vals, series.String, names[j]))
+		if df.Error() != nil {
+			return df
+		}
+	}
+	return df
+}
+
+// format converts a numeric value v, as formatted by strconv, to a value
+// written using the separators.
+func (n numberSpec) format(v string) string {
+	integer, fraction, found := strings.Cut(v, ".")
+	sign := ""
+	if len(integer) > 0 && (integer[0] == '-' || integer[0] == '+') {
+		sign, integer = integer[:1], integer[1:]
+	}
+	// group only plain digits, e.g., not '1e+06'
+	if n.ThousandsSeparator != "" && isDigits(integer) {
+		var b strings.Builder
+		for i, d := range integer {
+			if i > 0 && (len(integer)-i)%3 == 0 {
+				b.WriteString(n.ThousandsSeparator)
+			}
+			b.WriteRune(d)
+		}
+		integer = b.String()
+	}
+	if !found {
+		return sign + integer
+	}
+	return sign + integer + n.DecimalSeparator + fraction
+}
+
+// isDigits reports whether s consists of decimal digits only.
+func isDigits(s string) bool {
+	return strings.Trim(s, "0123456789") == ""
+}
+
+// floatFormatter returns the function used for formatting the floating
+// point values of the field name, which is either dflt, or a function
+// using FloatFormat and the field's precision.
+func (n numberSpec) floatFormatter(name string, dflt func(float64) string) func(float64) string {
+	p, found := n.Precision[name]
+	if n.FloatFormat == "" && !found {
+		return dflt
+	}
+	format := n.FloatFormat
+	if format == "" {
+		format = "%g"
+	}
+	if found {
+		m := floatFormatRegexp.FindStringSubmatch(format)
+		format = fmt.Sprintf("%%%v.%v%v", m[1], p, m[3])
+	}
+	return func(f float64) string {
+		return fmt.Sprintf(format, f)
+	}
+}
+
+// formatRecords returns the records of df, including the header,
+// with numeric values written using the separators and spellings.
+// Floating point values are formatted by floatFormatter, using dflt
+// as the default format.
+func (n numberSpec) formatRecords(df *dataframe.DataFrame, dflt func(float64) string) [][]string {
+	records := df.Records()
+	for j, name := range df.Names() {
+		col := df.Col(name)
+		t := col.Type()
+		if t != series.Float && t != series.Int {
+			continue
+		}
+		ff := n.floatFormatter(name, dflt)
+		for i, r := range records[1:] {
+			e := col.Elem(i)
+			var v string
+			switch f := e.Float(); {
+			case e.IsNA() || math.IsNaN(f):
+				r[j] = n.NaN
+				continue
+			case math.IsInf(f, 1):
+				r[j] = n.Inf
+				continue
+			case math.IsInf(f, -1):
+				r[j] = n.negInf()
+				continue
+			case t == series.Float:
+				v = ff(f)
+			default:
+				v = e.String()
+			}
+			r[j] = n.format(v)
+		}
+	}
+	return records
+}
+
+// formatFloat formats a floating point value as gota does.
+func formatFloat(f float64) string {
+	return fmt.Sprintf("%f", f)
+}
+
+// formatFloatExact formats a floating point value exactly, i.e., such that
+// it is parsed back unchanged, and always as a float, e.g., '2.0'.
+func formatFloatExact(f float64) string {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if isDigits(strings.TrimLeft(s, "-")) {
+		s += ".0"
+	}
+	return s
+}
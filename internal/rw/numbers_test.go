@@ -0,0 +1,176 @@
+package rw
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumberSpecValidate(t *testing.T) {
+	spec := func(fn func(*numberSpec)) numberSpec {
+		n := defaultNumberSpec()
+		fn(&n)
+		return n
+	}
+	for _, tt := range []struct {
+		Name  string
+		Spec  numberSpec
+		Error error
+	}{
+		{"good-default", defaultNumberSpec(), nil},
+		{"good-comma", spec(func(n *numberSpec) { n.DecimalSeparator, n.ThousandsSeparator = ",", "." }), nil},
+		{"good-space", spec(func(n *numberSpec) { n.ThousandsSeparator = " " }), nil},
+		{"good-format", spec(func(n *numberSpec) { n.FloatFormat = "%+10.3e" }), nil},
+		{"good-precision", spec(func(n *numberSpec) { n.Precision = map[string]int{"x": 0} }), nil},
+		{"bad-empty", spec(func(n *numberSpec) { n.DecimalSeparator = "" }), common.ErrBadFieldValue},
+		{"bad-long", spec(func(n *numberSpec) { n.DecimalSeparator = ",," }), common.ErrBadFieldValue},
+		{"bad-digit", spec(func(n *numberSpec) { n.ThousandsSeparator = "0" }), common.ErrBadFieldValue},
+		{"bad-same", spec(func(n *numberSpec) { n.DecimalSeparator, n.ThousandsSeparator = ",", "," }), common.ErrBadFieldValue},
+		{"bad-format-verb", spec(func(n *numberSpec) { n.FloatFormat = "%d" }), common.ErrBadFieldValue},
+		{"bad-format-text", spec(func(n *numberSpec) { n.FloatFormat = "x=%g" }), common.ErrBadFieldValue},
+		{"bad-precision", spec(func(n *numberSpec) { n.Precision = map[string]int{"x": -1} }), common.ErrBadFieldValue},
+	} {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.Spec.validate(), tt.Error)
+		})
+	}
+}
+
+func TestNumberSpecFormat(t *testing.T) {
+	n := defaultNumberSpec()
+	n.DecimalSeparator, n.ThousandsSeparator = ",", "."
+	for _, tt := range []struct {
+		Input  string
+		Output string
+	}{
+		{"0.500000", "0,500000"},
+		{"123", "123"},
+		{"1234", "1.234"},
+		{"-123456.5", "-123.456,5"},
+		{"1234567", "1.234.567"},
+		{"1.5e+06", "1,5e+06"},
+		{"+Inf", "+Inf"},
+	} {
+		t.Run(tt.Input, func(t *testing.T) {
+			assert.Equal(t, tt.Output, n.format(tt.Input))
+			v, ok := n.parse(tt.Output)
+			assert.True(t, ok)
+			assert.Equal(t, tt.Input, v)
+		})
+	}
+}
+
+func TestNumberSpecParse(t *testing.T) {
+	n := defaultNumberSpec()
+	n.DecimalSeparator, n.ThousandsSeparator = ",", "."
+	for _, tt := range []struct {
+		Input  string
+		Output string
+		OK     bool
+	}{
+		{"1.234.567,5", "1234567.5", true},
+		{"-12.345", "-12345", true},
+		{"12,5", "12.5", true},
+		{"1.5", "1.5", false},
+		{"12.05.2023", "12.05.2023", false},
+		{"1234.567", "1234.567", false},
+		{".123", ".123", false},
+		{"1,234.5", "1,234.5", false},
+		{"1,2,3", "1,2,3", false},
+		{"abc", "abc", false},
+	} {
+		t.Run(tt.Input, func(t *testing.T) {
+			out, ok := n.parse(tt.Input)
+			assert.Equal(t, tt.OK, ok)
+			assert.Equal(t, tt.Output, out)
+		})
+	}
+}
+
+func TestNumberSpecFormatRecords(t *testing.T) {
+	df := dataframe.New(
+		series.New([]float64{1.0 / 3, math.NaN(), math.Inf(1), math.Inf(-1)}, series.Float, "x"),
+		series.New([]float64{1234.5678, 0, 2, -0.5}, series.Float, "y"),
+		series.New([]interface{}{1, nil, 3, 4}, series.Int, "n"),
+		series.New([]string{"a", "b", "c", "d"}, series.String, "s"),
+	)
+	for _, tt := range []struct {
+		Name   string
+		Spec   func(*numberSpec)
+		Output [][]string
+	}{
+		{
+			Name: "good-default",
+			Spec: func(*numberSpec) {},
+			Output: [][]string{
+				{"x", "y", "n", "s"},
+				{"0.333333", "1234.567800", "1", "a"},
+				{"NaN", "0.000000", "NaN", "b"},
+				{"+Inf", "2.000000", "3", "c"},
+				{"-Inf", "-0.500000", "4", "d"},
+			},
+		},
+		{
+			Name: "good-format-precision",
+			Spec: func(n *numberSpec) {
+				n.FloatFormat = "%.3e"
+				n.Precision = map[string]int{"y": 1}
+			},
+			Output: [][]string{
+				{"x", "y", "n", "s"},
+				{"3.333e-01", "1.2e+03", "1", "a"},
+				{"NaN", "0.0e+00", "NaN", "b"},
+				{"+Inf", "2.0e+00", "3", "c"},
+				{"-Inf", "-5.0e-01", "4", "d"},
+			},
+		},
+		{
+			Name: "good-precision-spellings",
+			Spec: func(n *numberSpec) {
+				n.Precision = map[string]int{"x": 2}
+				n.NaN = "nan"
+				n.Inf = "inf"
+			},
+			Output: [][]string{
+				{"x", "y", "n", "s"},
+				{"0.33", "1234.567800", "1", "a"},
+				{"nan", "0.000000", "nan", "b"},
+				{"inf", "2.000000", "3", "c"},
+				{"-inf", "-0.500000", "4", "d"},
+			},
+		},
+		{
+			Name: "good-general-separators",
+			Spec: func(n *numberSpec) {
+				n.FloatFormat = "%g"
+				n.DecimalSeparator, n.ThousandsSeparator = ",", " "
+			},
+			Output: [][]string{
+				{"x", "y", "n", "s"},
+				{"0,3333333333333333", "1 234,5678", "1", "a"},
+				{"NaN", "0", "NaN", "b"},
+				{"+Inf", "2", "3", "c"},
+				{"-Inf", "-0,5", "4", "d"},
+			},
+		},
+	} {
+		t.Run(tt.Name, func(t *testing.T) {
+			n := defaultNumberSpec()
+			tt.Spec(&n)
+			assert.Nil(t, n.validate())
+			assert.Equal(t, tt.Output, n.formatRecords(&df, formatFloat))
+		})
+	}
+}
+
+func TestNumberSpecParseSpellings(t *testing.T) {
+	n := defaultNumberSpec()
+	n.NaN, n.Inf = "-", "inf"
+	records := [][]string{{"x"}, {"-"}, {"inf"}, {"-inf"}, {"1"}}
+	n.parseRecords(records, true, false)
+	assert.Equal(t, [][]string{{"x"}, {"NaN"}, {"+Inf"}, {"-Inf"}, {"1"}}, records)
+}
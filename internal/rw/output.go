@@ -20,8 +20,14 @@ var (
 type WriterFactory func(*yaml.Node) (Writer, error)
 
 var Writers = map[string]WriterFactory{
-	"csv": func(n *yaml.Node) (Writer, error) { return NewCsv(n) },
-	"ram": func(n *yaml.Node) (Writer, error) { return NewRam(n) },
+	"csv":            func(n *yaml.Node) (Writer, error) { return NewCsv(n) },
+	"dat":            func(n *yaml.Node) (Writer, error) { return NewDat(n) },
+	"fixed-width":    func(n *yaml.Node) (Writer, error) { return NewFixedWidth(n) },
+	"json":           func(n *yaml.Node) (Writer, error) { return NewJSON(n) },
+	"latex-table":    func(n *yaml.Node) (Writer, error) { return NewLaTeXTable(n) },
+	"markdown-table": func(n *yaml.Node) (Writer, error) { return NewMarkdownTable(n) },
+	"ndjson":         func(n *yaml.Node) (Writer, error) { return NewNDJSON(n) },
+	"ram":            func(n *yaml.Node) (Writer, error) { return NewRam(n) },
 }
 
 type Writer interface {
@@ -38,18 +44,46 @@ func Write(df *dataframe.DataFrame, configs []Config) error {
 	return err
 }
 
+// CheckOutput checks whether all outputs defined in the config can be
+// written, so that unsupported outputs are rejected before any data is
+// read or written.
+func CheckOutput(configs []Config) error {
+	var err error
+	for i := range configs {
+		err = errors.Join(err, checkOutput(&configs[i].TypeSpec))
+	}
+	return err
+}
+
 // write is a helper function which executes a single Writer.
 func write(df *dataframe.DataFrame, config *Config) error {
 	factory, found := Writers[strings.ToLower(config.Type)]
 	if !found {
 		return fmt.Errorf("%w, got: %q", ErrBadOutput, config.Type)
 	}
+	// reject unsupported output before anything is written
+	if err := checkOutput(&config.TypeSpec); err != nil {
+		return err
+	}
 	if common.Verbose {
 		log.Printf("output: writing: %q", strings.ToLower(config.Type))
 	}
-	w, err := factory(&config.TypeSpec)
+	if len(config.SplitBy) > 0 {
+		return writeSplit(df, config, factory)
+	}
+	return writeSpec(df, config, factory, &config.TypeSpec)
+}
+
+// writeSpec is a helper function which executes a single Writer created
+// by factory from spec.
+func writeSpec(df *dataframe.DataFrame, config *Config, factory WriterFactory, spec *yaml.Node) error {
+	w, err := factory(spec)
 	if err != nil {
 		return err
 	}
+	// decimate a copy, the full data is kept for other outputs
+	if df, err = decimate(df, config.MaxRows, &config.Decimation); err != nil {
+		return err
+	}
 	return w.Write(df)
 }
@@ -0,0 +1,268 @@
+package rw
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	datenc "github.com/Milover/post/internal/encoding/dat"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrProbesLocations = errors.New("probes: probe location and probe field counts differ")
+	ErrProbesShape     = errors.New("probes: inconsistent probe field shapes")
+)
+
+var (
+	probeLocationRegexp = regexp.MustCompile(
+		`^\s*Probe\s+(\d+)\s+\(\s*(\S+)\s+(\S+)\s+(\S+)\s*\)`)
+)
+
+// probes contains data needed for parsing an OpenFOAM probes
+// functionObject output file, which is of the following format:
+//
+//	# Probe 0 (0 0 0)
+//	# Probe 1 (1 0 0)
+//	#       Probe             0             1
+//	#        Time
+//	0.1    1    2
+//	0.2    3    4
+//
+// where each probe's values are written in a separate column, and vector
+// or tensor values are written as tuples, e.g., '(1 2 3)'.
+type probes struct {
+	// File is the file path of the probes file.
+	File string `yaml:"file"`
+	// Field is the name of the probed field.
+	// If left empty, the base name of File is used.
+	Field string `yaml:"field"`
+	// TimeName is the name of the time field.
+	// If left empty it is set to 'time'.
+	TimeName string `yaml:"time_name"`
+	// Long toggles long format output, i.e., one row per time and probe,
+	// with the probe index and location fields, instead of one row
+	// per time and a field for each probe.
+	Long bool `yaml:"long"`
+	// Locations toggles the output of the probe location fields in wide
+	// format, i.e., 'probe0_x', 'probe0_y', 'probe0_z', ..., holding the
+	// location of each probe in every row. The probe locations are always
+	// output in long format.
+	Locations bool `yaml:"locations"`
+
+	componentSpec `yaml:",inline"`
+
+	// locations are the probe locations, read from the header.
+	locations []probeLocation
+}
+
+// probeLocation is the index and location of a single probe.
+type probeLocation struct {
+	ID      int
+	X, Y, Z float64
+}
+
+func defaultProbes() *probes {
+	return &probes{
+		TimeName:      "time",
+		componentSpec: defaultComponentSpec(),
+	}
+}
+
+func NewProbes(n *yaml.Node) (*probes, error) {
+	rw := defaultProbes()
+	if err := n.Decode(rw); err != nil {
+		return nil, fmt.Errorf("probes: %w", err)
+	}
+	if err := rw.componentSpec.validate(); err != nil {
+		return nil, fmt.Errorf("probes: %w", err)
+	}
+	if rw.Field == "" && rw.File != "" {
+		rw.Field = filepath.Base(rw.File)
+	}
+	if rw.Field == "" {
+		rw.Field = "value"
+	}
+	return rw, nil
+}
+
+func (rw *probes) Read() (*dataframe.DataFrame, error) {
+	fn := func(name string) (io.ReadCloser, error) {
+		return os.Open(name)
+	}
+	return rw.ReadFromFn(fn)
+}
+
+func (rw *probes) ReadFromFn(fn ReaderFunc) (*dataframe.DataFrame, error) {
+	rc, err := fn(rw.File)
+	if err != nil {
+		return nil, fmt.Errorf("probes: %w", err)
+	}
+	defer rc.Close()
+	return rw.read(rc)
+}
+
+// readLocations parses the probe locations from the header comments.
+func (rw *probes) readLocations(comments []string) error {
+	rw.locations = rw.locations[:0]
+	for _, c := range comments {
+		m := probeLocationRegexp.FindStringSubmatch(c)
+		if m == nil {
+			continue
+		}
+		var l probeLocation
+		var err error
+		if l.ID, err = strconv.Atoi(m[1]); err != nil {
+			return err
+		}
+		for i, v := range []*float64{&l.X, &l.Y, &l.Z} {
+			if *v, err = strconv.ParseFloat(m[i+2], 64); err != nil {
+				return err
+			}
+		}
+		rw.locations = append(rw.locations, l)
+	}
+	return nil
+}
+
+func (rw *probes) read(in io.Reader) (*dataframe.DataFrame, error) {
+	r := datenc.NewReader(in)
+	comments, err := r.ReadComments()
+	if err != nil {
+		return nil, fmt.Errorf("probes: %w", err)
+	}
+	if err := rw.readLocations(comments); err != nil {
+		return nil, fmt.Errorf("probes: %w", err)
+	}
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("probes: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("probes: %w", io.ErrUnexpectedEOF)
+	}
+	shapes := r.Shapes()[1:] // the first field is the time
+	nProbes := len(shapes)
+	if len(rw.locations) != nProbes && (rw.Long || rw.Locations || len(rw.locations) != 0) {
+		return nil, fmt.Errorf("%w: %v != %v",
+			ErrProbesLocations, len(rw.locations), nProbes)
+	}
+	nComp := 0
+	if nProbes > 0 {
+		nComp = shapes[0].Len()
+	}
+	for _, s := range shapes {
+		if s.Len() != nComp {
+			return nil, ErrProbesShape
+		}
+	}
+
+	// parse all values
+	values := make([][]float64, 1+nProbes*nComp) // column-major
+	for i := range values {
+		values[i] = make([]float64, len(records))
+	}
+	for i, rec := range records {
+		if len(rec) != len(values) {
+			return nil, fmt.Errorf("%w: in record: %v", ErrProbesShape, i)
+		}
+		for j := range rec {
+			if values[j][i], err = strconv.ParseFloat(rec[j], 64); err != nil {
+				return nil, fmt.Errorf("probes: %w", err)
+			}
+		}
+	}
+	times := values[0]
+
+	var df dataframe.DataFrame
+	if rw.Long {
+		df = rw.long(times, values[1:], shapes, nComp)
+	} else {
+		ss := make([]series.Series, 0, len(values))
+		ss = append(ss, series.New(times, series.Float, rw.TimeName))
+		for i, s := range shapes {
+			names := rw.names(rw.Field+"_probe"+strconv.Itoa(rw.id(i)), nil, s)
+			for j, name := range names {
+				ss = append(ss, series.New(values[1+i*nComp+j], series.Float, name))
+			}
+		}
+		if rw.Locations {
+			ss = append(ss, rw.wideLocations(len(times))...)
+		}
+		df = dataframe.New(ss...)
+	}
+	if df.Error() != nil {
+		return nil, fmt.Errorf("probes: %w", df.Error())
+	}
+	return &df, nil
+}
+
+// id returns the index of the i-th probe.
+func (rw *probes) id(i int) int {
+	if len(rw.locations) == 0 {
+		return i
+	}
+	return rw.locations[i].ID
+}
+
+// wideLocations returns the probe location fields of a wide format
+// dataframe.DataFrame with nRows rows.
+func (rw *probes) wideLocations(nRows int) []series.Series {
+	ss := make([]series.Series, 0, 3*len(rw.locations))
+	for _, l := range rw.locations {
+		prefix := "probe" + strconv.Itoa(l.ID) + "_"
+		for i, v := range []float64{l.X, l.Y, l.Z} {
+			vals := make([]float64, nRows)
+			for j := range vals {
+				vals[j] = v
+			}
+			ss = append(ss, series.New(vals, series.Float, prefix+string("xyz"[i])))
+		}
+	}
+	return ss
+}
+
+// long assembles a long format dataframe.DataFrame, with one row per
+// time and probe, from the time values and probe values, where values
+// holds nComp component columns for each probe.
+func (rw *probes) long(times []float64, values [][]float64, shapes []datenc.Shape, nComp int) dataframe.DataFrame {
+	nRows := len(times) * len(shapes)
+	t := make([]float64, 0, nRows)
+	id := make([]int, 0, nRows)
+	x := make([]float64, 0, nRows)
+	y := make([]float64, 0, nRows)
+	z := make([]float64, 0, nRows)
+	comps := make([][]float64, nComp)
+	for i := range comps {
+		comps[i] = make([]float64, 0, nRows)
+	}
+	for i := range times {
+		for j, l := range rw.locations {
+			t = append(t, times[i])
+			id = append(id, l.ID)
+			x, y, z = append(x, l.X), append(y, l.Y), append(z, l.Z)
+			for k := range comps {
+				comps[k] = append(comps[k], values[j*nComp+k][i])
+			}
+		}
+	}
+	ss := []series.Series{
+		series.New(t, series.Float, rw.TimeName),
+		series.New(id, series.Int, "probe"),
+		series.New(x, series.Float, "x"),
+		series.New(y, series.Float, "y"),
+		series.New(z, series.Float, "z"),
+	}
+	if len(shapes) > 0 {
+		for k, name := range rw.names(rw.Field, nil, shapes[0]) {
+			ss = append(ss, series.New(comps[k], series.Float, name))
+		}
+	}
+	return dataframe.New(ss...)
+}
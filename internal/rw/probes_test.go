@@ -0,0 +1,177 @@
+package rw
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type probesTest struct {
+	Name      string
+	Config    string
+	Input     string
+	Output    dataframe.DataFrame
+	Locations []probeLocation
+	Error     error
+}
+
+const probesScalar string = `# Probe 0 (0 0 0)
+# Probe 2 (1 0.5 0)
+#       Probe             0             2
+#        Time
+0.1	1	2
+0.2	3	4
+`
+
+const probesVector string = `# Probe 0 (0 0 0)
+# Probe 1 (1 0 0)
+#       Probe             0             1
+#        Time
+0.1	(1 2 3)	(4 5 6)
+`
+
+var probesReadTests = []probesTest{
+	{
+		Name: "good-scalar",
+		Config: `
+field: p
+`,
+		Input: probesScalar,
+		Output: dataframe.New(
+			series.New([]float64{0.1, 0.2}, series.Float, "time"),
+			series.New([]float64{1, 3}, series.Float, "p_probe0"),
+			series.New([]float64{2, 4}, series.Float, "p_probe2"),
+		),
+		Locations: []probeLocation{{0, 0, 0, 0}, {2, 1, 0.5, 0}},
+		Error:     nil,
+	},
+	{
+		Name: "good-vector",
+		Config: `
+file: postProcessing/probes/0/U
+`,
+		Input: probesVector,
+		Output: dataframe.New(
+			series.New([]float64{0.1}, series.Float, "time"),
+			series.New([]float64{1}, series.Float, "U_probe0_x"),
+			series.New([]float64{2}, series.Float, "U_probe0_y"),
+			series.New([]float64{3}, series.Float, "U_probe0_z"),
+			series.New([]float64{4}, series.Float, "U_probe1_x"),
+			series.New([]float64{5}, series.Float, "U_probe1_y"),
+			series.New([]float64{6}, series.Float, "U_probe1_z"),
+		),
+		Locations: []probeLocation{{0, 0, 0, 0}, {1, 1, 0, 0}},
+		Error:     nil,
+	},
+	{
+		Name: "good-scalar-locations",
+		Config: `
+field: p
+locations: true
+`,
+		Input: probesScalar,
+		Output: dataframe.New(
+			series.New([]float64{0.1, 0.2}, series.Float, "time"),
+			series.New([]float64{1, 3}, series.Float, "p_probe0"),
+			series.New([]float64{2, 4}, series.Float, "p_probe2"),
+			series.New([]float64{0, 0}, series.Float, "probe0_x"),
+			series.New([]float64{0, 0}, series.Float, "probe0_y"),
+			series.New([]float64{0, 0}, series.Float, "probe0_z"),
+			series.New([]float64{1, 1}, series.Float, "probe2_x"),
+			series.New([]float64{0.5, 0.5}, series.Float, "probe2_y"),
+			series.New([]float64{0, 0}, series.Float, "probe2_z"),
+		),
+		Locations: []probeLocation{{0, 0, 0, 0}, {2, 1, 0.5, 0}},
+		Error:     nil,
+	},
+	{
+		Name: "good-scalar-long",
+		Config: `
+field: p
+long: true
+`,
+		Input: probesScalar,
+		Output: dataframe.New(
+			series.New([]float64{0.1, 0.1, 0.2, 0.2}, series.Float, "time"),
+			series.New([]int{0, 2, 0, 2}, series.Int, "probe"),
+			series.New([]float64{0, 1, 0, 1}, series.Float, "x"),
+			series.New([]float64{0, 0.5, 0, 0.5}, series.Float, "y"),
+			series.New([]float64{0, 0, 0, 0}, series.Float, "z"),
+			series.New([]float64{1, 2, 3, 4}, series.Float, "p"),
+		),
+		Locations: []probeLocation{{0, 0, 0, 0}, {2, 1, 0.5, 0}},
+		Error:     nil,
+	},
+	{
+		Name: "good-vector-long",
+		Config: `
+field: U
+long: true
+component_suffix: index
+`,
+		Input: probesVector,
+		Output: dataframe.New(
+			series.New([]float64{0.1, 0.1}, series.Float, "time"),
+			series.New([]int{0, 1}, series.Int, "probe"),
+			series.New([]float64{0, 1}, series.Float, "x"),
+			series.New([]float64{0, 0}, series.Float, "y"),
+			series.New([]float64{0, 0}, series.Float, "z"),
+			series.New([]float64{1, 4}, series.Float, "U_0"),
+			series.New([]float64{2, 5}, series.Float, "U_1"),
+			series.New([]float64{3, 6}, series.Float, "U_2"),
+		),
+		Locations: []probeLocation{{0, 0, 0, 0}, {1, 1, 0, 0}},
+		Error:     nil,
+	},
+	{
+		Name: "bad-locations",
+		Config: `
+long: true
+`,
+		Input:     "# Probe 0 (0 0 0)\n# Time\n0.1 1 2\n",
+		Output:    dataframe.DataFrame{},
+		Locations: nil,
+		Error:     ErrProbesLocations,
+	},
+	{
+		Name: "bad-locations-wide",
+		Config: `
+locations: true
+`,
+		Input:     "# Time\n0.1 1 2\n",
+		Output:    dataframe.DataFrame{},
+		Locations: nil,
+		Error:     ErrProbesLocations,
+	},
+}
+
+func TestProbesRead(t *testing.T) {
+	for _, tt := range probesReadTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			raw, err := io.ReadAll(strings.NewReader(tt.Config))
+			assert.Nil(err, "unexpected io.ReadAll() error")
+			var config yaml.Node
+			err = yaml.Unmarshal(raw, &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+
+			rw, err := NewProbes(&config)
+			assert.Nil(err, "unexpected NewProbes() error")
+			out, err := rw.read(strings.NewReader(tt.Input))
+
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error != nil {
+				assert.Nil(out)
+			} else {
+				assert.Equal(tt.Output, *out)
+				assert.Equal(tt.Locations, rw.locations)
+			}
+		})
+	}
+}
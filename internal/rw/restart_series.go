@@ -0,0 +1,173 @@
+package rw
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"path"
+	"slices"
+
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrRestartSeriesTime   = errors.New("restart-series: time field not found")
+	ErrRestartSeriesFields = errors.New("restart-series: files differ in fields")
+)
+
+// restartSeries contains data needed for reading the output of
+// a functionObject from a restarted run, which is of the following format:
+//
+//	.
+//	├── 0
+//	│   └── p
+//	├── 10.5
+//	│   └── p
+//	└── ...
+//
+// where each directory is named after the start time of a (re)started run
+// and contains the data written by that run.
+// The data of each start-time directory is concatenated in order of
+// start time, and where time ranges overlap, the data of the later restart
+// wins, i.e., rows of earlier restarts whose times are not lower than the
+// earliest time of a later restart are discarded.
+type restartSeries struct {
+	// File is the file name of the data files.
+	File string `yaml:"file"`
+	// Directory is the root directory containing the start-time directories.
+	Directory string `yaml:"directory"`
+	// TimeName is the name of the time field of the data files.
+	// If left empty it is set to 'time'.
+	TimeName string `yaml:"time_name"`
+	// SkipNonNumeric determines whether directories whose names are not
+	// numbers are skipped. If it is not set, such directories result
+	// in an error.
+	SkipNonNumeric bool `yaml:"skip_non_numeric"`
+	// FormatSpec is the config for the data file type input,
+	// e.g., if the data files are probes files, FormatSpec would define
+	// a config for a probes input type.
+	FormatSpec Config `yaml:"format_spec"`
+}
+
+func defaultRestartSeries() *restartSeries {
+	return &restartSeries{
+		TimeName: "time",
+	}
+}
+
+func NewRestartSeries(n *yaml.Node) (*restartSeries, error) {
+	rw := defaultRestartSeries()
+	if err := n.Decode(rw); err != nil {
+		return nil, fmt.Errorf("restart-series: %w", err)
+	}
+	if rw.File == "" {
+		return nil, fmt.Errorf("restart-series: %w: %q", common.ErrUnsetField, "file")
+	}
+	if rw.Directory == "" {
+		return nil, fmt.Errorf("restart-series: %w: %q", common.ErrUnsetField, "directory")
+	}
+	return rw, nil
+}
+
+func (rw *restartSeries) Read() (*dataframe.DataFrame, error) {
+	if _, err := os.Stat(rw.Directory); err != nil {
+		return nil, fmt.Errorf("restart-series: %w", err)
+	}
+	return rw.read(os.DirFS(rw.Directory))
+}
+
+func (rw *restartSeries) ReadFromFn(fn ReaderFunc) (*dataframe.DataFrame, error) {
+	in, err := fn(rw.Directory)
+	if err != nil {
+		return nil, fmt.Errorf("restart-series: %w", err)
+	}
+	fsys, ok := in.(fs.FS)
+	if !ok {
+		return nil, fmt.Errorf("restart-series: %w to 'fs.FS'", common.ErrBadCast)
+	}
+	return rw.read(fsys)
+}
+
+func (rw *restartSeries) read(fsys fs.FS) (*dataframe.DataFrame, error) {
+	dirs, err := readTimeDirs(fsys, rw.SkipNonNumeric)
+	if err != nil {
+		return nil, fmt.Errorf("restart-series: %w", err)
+	}
+	var dfs []*dataframe.DataFrame
+	var files []string
+	for _, dir := range dirs {
+		file := path.Join(dir.Name, rw.File)
+		// start-time directories without the data file are skipped
+		if _, err := fs.Stat(fsys, file); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if common.Verbose {
+			log.Printf("restart-series: reading: %v", file)
+		}
+		fn := func(_ string) (io.ReadCloser, error) {
+			return fsys.Open(file)
+		}
+		temp, err := ReadFromFn(fn, &rw.FormatSpec)
+		if err != nil {
+			return nil, fmt.Errorf("restart-series: %w: in file: %v", err, file)
+		}
+		if !slices.Contains(temp.Names(), rw.TimeName) {
+			return nil, fmt.Errorf("%w: %q: in file: %v", ErrRestartSeriesTime, rw.TimeName, file)
+		}
+		dfs = append(dfs, temp)
+		files = append(files, file)
+	}
+
+	// discard overlapping rows, going from the latest restart backwards,
+	// so that later restarts win
+	cutoff := math.Inf(1)
+	for i := len(dfs) - 1; i >= 0; i-- {
+		times := dfs[i].Col(rw.TimeName).Float()
+		keep := make([]int, 0, len(times))
+		first := math.Inf(1)
+		for j, t := range times {
+			if t < cutoff {
+				keep = append(keep, j)
+			}
+			first = math.Min(first, t)
+		}
+		cutoff = math.Min(cutoff, first)
+		if len(keep) == len(times) {
+			continue
+		}
+		if len(keep) == 0 {
+			dfs[i] = nil
+			continue
+		}
+		*dfs[i] = dfs[i].Subset(keep)
+		if dfs[i].Error() != nil {
+			return nil, fmt.Errorf("restart-series: %w: in file: %v", dfs[i].Error(), files[i])
+		}
+	}
+
+	kept := make([]*dataframe.DataFrame, 0, len(dfs))
+	for i, temp := range dfs {
+		if temp == nil {
+			continue
+		}
+		if len(kept) > 0 && !sameFields(temp.Names(), kept[0].Names()) {
+			return nil, fmt.Errorf("%w: %q != %q: in file: %v",
+				ErrRestartSeriesFields, temp.Names(), kept[0].Names(), files[i])
+		}
+		kept = append(kept, temp)
+	}
+	if len(kept) == 0 {
+		return nil, nil
+	}
+	df, err := rbind(kept)
+	if err != nil {
+		return nil, fmt.Errorf("restart-series: %w", err)
+	}
+	return &df, nil
+}
@@ -0,0 +1,73 @@
+package rw
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type restartSeriesTest struct {
+	Name   string
+	Config string
+	Output dataframe.DataFrame
+	Error  error
+}
+
+var restartSeriesReadTests = []restartSeriesTest{
+	{
+		Name: "good",
+		Config: `
+directory: 'testdata/restart_series'
+file: data.csv
+format_spec:
+  type: csv
+`,
+		Output: dataframe.New(
+			series.New([]float64{0, 5, 10.5, 15, 20, 25}, series.Float, "time"),
+			series.New([]int{1, 2, 30, 40, 50, 60}, series.Int, "p"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "bad-time-name",
+		Config: `
+directory: 'testdata/restart_series'
+file: data.csv
+time_name: t
+format_spec:
+  type: csv
+`,
+		Output: dataframe.DataFrame{},
+		Error:  ErrRestartSeriesTime,
+	},
+}
+
+func TestRestartSeriesRead(t *testing.T) {
+	for _, tt := range restartSeriesReadTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			raw, err := io.ReadAll(strings.NewReader(tt.Config))
+			assert.Nil(err, "unexpected io.ReadAll() error")
+			var config yaml.Node
+			err = yaml.Unmarshal(raw, &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+
+			rw, err := NewRestartSeries(&config)
+			assert.Nil(err, "unexpected NewRestartSeries() error")
+			out, err := rw.Read()
+
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error != nil {
+				assert.Nil(out)
+			} else {
+				assert.Equal(tt.Output, *out)
+			}
+		})
+	}
+}
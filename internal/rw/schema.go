@@ -0,0 +1,239 @@
+package rw
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"gopkg.in/yaml.v3"
+)
+
+// Schema field types.
+const (
+	SchemaString string = "string"
+	SchemaInt    string = "int"
+	SchemaFloat  string = "float"
+	SchemaBool   string = "bool"
+	SchemaTime   string = "time"
+
+	// SchemaDatetime is an alias of SchemaTime.
+	SchemaDatetime string = "datetime"
+)
+
+var (
+	ErrSchemaParse = errors.New("schema: cannot parse value")
+)
+
+// FieldSchema is the schema of a single field.
+// It can be defined by the type name only, e.g., 'int', or by a mapping.
+type FieldSchema struct {
+	// Type is the field type, one of 'string', 'int', 'float', 'bool'
+	// or 'time' ('datetime'). Time values are converted to seconds since
+	// the epoch, as defined by the datetime specification.
+	Type string `yaml:"type"`
+	// Missing is a list of tokens denoting missing values, which are read
+	// as NaN, in addition to empty values and 'NaN', e.g., 'N/A' or '-'.
+	Missing []string `yaml:"missing"`
+
+	datetime `yaml:",inline"`
+}
+
+func (s *FieldSchema) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		s.Type = n.Value
+	} else {
+		type raw FieldSchema
+		if err := n.Decode((*raw)(s)); err != nil {
+			return err
+		}
+	}
+	s.Type = strings.ToLower(s.Type)
+	switch s.Type {
+	case SchemaString, SchemaInt, SchemaFloat, SchemaBool:
+		return nil
+	case SchemaTime, SchemaDatetime:
+		s.Type = SchemaTime
+		return s.datetime.init()
+	}
+	return fmt.Errorf("schema: %w: %q: %q", common.ErrBadFieldValue, "type", s.Type)
+}
+
+// isMissing reports whether v denotes a missing value.
+func (s *FieldSchema) isMissing(v string) bool {
+	return v == "" || v == "NaN" || slices.Contains(s.Missing, v)
+}
+
+// parse parses a value and returns the value as the field type.
+// Time values are returned as time.Time.
+func (s *FieldSchema) parse(v string) (any, error) {
+	switch s.Type {
+	case SchemaString:
+		return v, nil
+	case SchemaInt:
+		i, err := strconv.Atoi(v)
+		if err == nil {
+			return i, nil
+		}
+		// allow integral floats, e.g., from float fields
+		if f, ferr := strconv.ParseFloat(v, 64); ferr == nil &&
+			f == math.Trunc(f) && math.Abs(f) <= math.MaxInt {
+			return int(f), nil
+		}
+		return nil, err
+	case SchemaFloat:
+		return strconv.ParseFloat(v, 64)
+	case SchemaBool:
+		return strconv.ParseBool(v)
+	case SchemaTime:
+		return s.datetime.parse(v)
+	}
+	return nil, fmt.Errorf("%w: %q: %q", common.ErrBadFieldValue, "type", s.Type)
+}
+
+// seriesType returns the series.Type of the field type.
+func (s *FieldSchema) seriesType() series.Type {
+	switch s.Type {
+	case SchemaString:
+		return series.String
+	case SchemaInt:
+		return series.Int
+	case SchemaBool:
+		return series.Bool
+	}
+	return series.Float
+}
+
+// rawReader is implemented by input types which can read all fields as
+// unparsed strings, so that a schema can be applied to the original values.
+type rawReader interface {
+	setRaw()
+}
+
+// rawOptions appends options which turn off type detection, i.e., read all
+// fields as strings, to opts if raw is set.
+func rawOptions(opts []dataframe.LoadOption, raw bool) []dataframe.LoadOption {
+	if !raw {
+		return opts
+	}
+	return append(opts,
+		dataframe.DetectTypes(false),
+		dataframe.DefaultType(series.String),
+	)
+}
+
+// setRaw sets r to read all fields as unparsed strings, if r is a rawReader
+// and a schema is defined by config, and reports whether it was set.
+func setRaw(r any, config *Config) bool {
+	if len(config.Schema) == 0 {
+		return false
+	}
+	rr, ok := r.(rawReader)
+	if ok {
+		rr.setRaw()
+	}
+	return ok
+}
+
+// applySchema converts the fields of df to the types defined by the schema
+// of config. Fields not defined by the schema, which were read as unparsed
+// strings, i.e., if raw is set, have their types inferred from their values.
+// If config.Strict is set, values which cannot be parsed result in an error,
+// otherwise they are set to NaN. A nil df, e.g., if no data was selected,
+// is returned as is.
+func applySchema(df *dataframe.DataFrame, config *Config, raw bool) (*dataframe.DataFrame, error) {
+	if df == nil || len(config.Schema) == 0 {
+		return df, nil
+	}
+	if err := checkSchema(df, config); err != nil {
+		return nil, err
+	}
+	ss := make([]series.Series, df.Ncol())
+	var inferred []string // names of fields whose types are inferred
+	for j, name := range df.Names() {
+		col := df.Col(name)
+		s, found := config.Schema[name]
+		if !found {
+			ss[j] = col
+			if raw {
+				inferred = append(inferred, name)
+			}
+			continue
+		}
+		vals := make([]interface{}, col.Len())
+		for i, v := range col.Records() {
+			if s.isMissing(v) {
+				continue
+			}
+			var err error
+			if vals[i], err = s.parse(v); err != nil {
+				if config.Strict {
+					return nil, fmt.Errorf("%w: %vrow %v (0-based), field %q: %q: %v",
+						ErrSchemaParse, config.file(), i, name, v, err)
+				}
+				vals[i] = nil
+			}
+		}
+		if s.Type == SchemaTime {
+			ts := make([]time.Time, len(vals))
+			for i, v := range vals {
+				if v != nil {
+					ts[i] = v.(time.Time)
+				}
+			}
+			ss[j] = series.New(s.datetime.seconds(ts), series.Float, name)
+			continue
+		}
+		ss[j] = series.New(vals, s.seriesType(), name)
+	}
+	if len(inferred) > 0 && df.Nrow() > 0 {
+		records := make([][]string, df.Nrow()+1)
+		records[0] = inferred
+		for i := range records[1:] {
+			records[i+1] = make([]string, len(inferred))
+		}
+		for k, name := range inferred {
+			for i, v := range df.Col(name).Records() {
+				records[i+1][k] = v
+			}
+		}
+		temp := dataframe.LoadRecords(records,
+			dataframe.HasHeader(true),
+			dataframe.DefaultType(series.Float),
+		)
+		if temp.Error() != nil {
+			return nil, fmt.Errorf("schema: %w", temp.Error())
+		}
+		for j, name := range df.Names() {
+			if slices.Contains(inferred, name) {
+				ss[j] = temp.Col(name)
+			}
+		}
+	}
+	out := dataframe.New(ss...)
+	if out.Error() != nil {
+		return nil, fmt.Errorf("schema: %w", out.Error())
+	}
+	return &out, nil
+}
+
+// checkSchema checks whether all fields defined by the schema of config
+// are present in df.
+func checkSchema(df *dataframe.DataFrame, config *Config) error {
+	if df == nil {
+		return errors.New("schema: no data")
+	}
+	names := df.Names()
+	for name := range config.Schema {
+		if !slices.Contains(names, name) {
+			return fmt.Errorf("schema: %w: %q", common.ErrBadField, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package rw
+
+import (
+	"testing"
+
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type schemaTest struct {
+	Name   string
+	Config string
+	Output dataframe.DataFrame
+	Error  error
+}
+
+var schemaTests = []schemaTest{
+	{
+		Name: "good",
+		Config: `
+type: csv
+type_spec:
+  file: testdata/schema.csv
+schema:
+  id: int
+  name:
+    type: string
+    missing: [N/A]
+  value:
+    type: float
+    missing: ['-']
+  flag: bool
+  date: time
+`,
+		Output: dataframe.New(
+			series.New([]int{1, 2, 3}, series.Int, "id"),
+			series.New([]interface{}{"a", nil, "b"}, series.String, "name"),
+			series.New([]interface{}{1.5, nil, 2.0}, series.Float, "value"),
+			series.New([]bool{true, false, true}, series.Bool, "flag"),
+			series.New([]interface{}{1704153600.0, 1704240000.0, nil}, series.Float, "date"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-infer",
+		Config: `
+type: csv
+type_spec:
+  file: testdata/data.csv
+schema:
+  x: float
+`,
+		Output: dataframe.New(
+			series.New([]float64{0, 1, 2, 3, 4, 5}, series.Float, "x"),
+			series.New([]int{0, 1, 2, 2, 1, 0}, series.Int, "y"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-keep-string",
+		Config: `
+type: csv
+type_spec:
+  file: testdata/schema.csv
+fields: [key, name, value, flag, date]
+schema:
+  key: string
+`,
+		Output: dataframe.New(
+			series.New([]string{"001", "002", "3.0"}, series.String, "key"),
+			series.New([]string{"a", "N/A", "b"}, series.String, "name"),
+			series.New([]string{"1.5", "-", "2"}, series.String, "value"),
+			series.New([]bool{true, false, true}, series.Bool, "flag"),
+			series.New([]string{"2024-01-02T00:00:00Z", "2024-01-03", ""}, series.String, "date"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-not-raw",
+		Config: `
+type: time-series
+type_spec:
+  directory: testdata/foam_series.good
+  file: data.csv
+  format_spec:
+    type: csv
+schema:
+  x: float
+`,
+		Output: dataframe.New(
+			expectedSeries.Col("time"),
+			series.New(expectedSeries.Col("x").Float(), series.Float, "x"),
+			expectedSeries.Col("y"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-non-strict",
+		Config: `
+type: csv
+type_spec:
+  file: testdata/schema.csv
+schema:
+  value: float
+`,
+		Output: dataframe.New(
+			series.New([]float64{1, 2, 3}, series.Float, "id"),
+			series.New([]string{"a", "N/A", "b"}, series.String, "name"),
+			series.New([]interface{}{1.5, nil, 2.0}, series.Float, "value"),
+			series.New([]bool{true, false, true}, series.Bool, "flag"),
+			series.New([]string{"2024-01-02T00:00:00Z", "2024-01-03", ""}, series.String, "date"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-datetime-first",
+		Config: `
+type: csv
+type_spec:
+  file: testdata/datetime.csv
+schema:
+  stamp:
+    type: datetime
+    layout: '%d/%m/%Y %H:%M:%S'
+    epoch: first
+`,
+		Output: dataframe.New(
+			series.New([]float64{0, 30.5, 3600}, series.Float, "stamp"),
+			series.New([]int{1, 2, 3}, series.Int, "value"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-datetime-timezone",
+		Config: `
+type: csv
+type_spec:
+  file: testdata/datetime.csv
+schema:
+  stamp:
+    type: time
+    layout: 02/01/2006 15:04:05
+    timezone: Europe/Zagreb
+`,
+		Output: dataframe.New(
+			series.New([]float64{1710658800, 1710658830.5, 1710662400}, series.Float, "stamp"),
+			series.New([]int{1, 2, 3}, series.Int, "value"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-datetime-epoch",
+		Config: `
+type: csv
+type_spec:
+  file: testdata/datetime.csv
+schema:
+  stamp:
+    type: time
+    layout: '%d/%m/%Y %H:%M:%S'
+    epoch: 17/03/2024 07:00:00
+`,
+		Output: dataframe.New(
+			series.New([]float64{3600, 3630.5, 7200}, series.Float, "stamp"),
+			series.New([]int{1, 2, 3}, series.Int, "value"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "bad-strict",
+		Config: `
+type: csv
+type_spec:
+  file: testdata/schema.csv
+schema:
+  value: float
+strict: true
+`,
+		Output: dataframe.DataFrame{},
+		Error:  ErrSchemaParse,
+	},
+	{
+		Name: "bad-field",
+		Config: `
+type: csv
+type_spec:
+  file: testdata/schema.csv
+schema:
+  nope: float
+`,
+		Output: dataframe.DataFrame{},
+		Error:  common.ErrBadField,
+	},
+}
+
+func TestSchema(t *testing.T) {
+	for _, tt := range schemaTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var config Config
+			err := yaml.Unmarshal([]byte(tt.Config), &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+			out, err := Read(&config)
+
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error != nil {
+				assert.Nil(out)
+			} else {
+				assert.Equal(tt.Output, *out)
+			}
+		})
+	}
+}
+
+func TestFieldSchemaUnmarshal(t *testing.T) {
+	for _, tt := range []struct {
+		Config string
+		Error  error
+	}{
+		{"schema: {x: decimal}", common.ErrBadFieldValue},
+		{"schema: {x: {type: time, timezone: Nowhere/Nothing}}", common.ErrBadFieldValue},
+		{"schema: {x: {type: time, epoch: yesterday}}", common.ErrBadFieldValue},
+		{"schema: {x: {type: time, layout: '%Y-%Q'}}", ErrStrftime},
+	} {
+		t.Run(tt.Config, func(t *testing.T) {
+			var config Config
+			err := yaml.Unmarshal([]byte(tt.Config), &config)
+			assert.ErrorIs(t, err, tt.Error)
+		})
+	}
+}
+
+func TestSchemaNilFrame(t *testing.T) {
+	assert := assert.New(t)
+
+	var config Config
+	err := yaml.Unmarshal([]byte(`
+type: time-series
+type_spec:
+  directory: testdata/foam_series.good
+  file: data.csv
+  skip_non_numeric: true
+  start_time: 100
+  format_spec:
+    type: csv
+schema:
+  x: float
+`), &config)
+	assert.Nil(err, "unexpected yaml.Unmarshal() error")
+	out, err := Read(&config)
+
+	assert.Nil(err)
+	assert.Nil(out)
+}
+
+func TestConfigIsEmptyStrict(t *testing.T) {
+	var config Config
+	err := yaml.Unmarshal([]byte("strict: true"), &config)
+	assert.Nil(t, err, "unexpected yaml.Unmarshal() error")
+	assert.False(t, config.IsEmpty())
+}
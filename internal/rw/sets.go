@@ -0,0 +1,246 @@
+package rw
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Milover/post/internal/common"
+	datenc "github.com/Milover/post/internal/encoding/dat"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrSetsName  = errors.New("sets: cannot derive field names from file name")
+	ErrSetsShape = errors.New("sets: field and column counts do not match")
+)
+
+// sets contains data needed for parsing OpenFOAM sampled sets output,
+// i.e., output of the 'sets' functionObject or 'sample' utility,
+// which is of the following format:
+//
+//	.
+//	├── 0.1
+//	│   ├── lineA_p_T.xy
+//	│   ├── lineA_U.xy
+//	│   └── ...
+//	├── 0.2
+//	│   └── ...
+//	└── ...
+//
+// where the file name consists of the set name and the names of the
+// fields contained in the file, separated by underscores, and each file
+// contains the coordinate(s) of the sampling points followed by the
+// field values.
+type sets struct {
+	// File is the file name of the set data file, e.g., 'lineA_p_U.xy'.
+	// If Directory is set, File should only be the base name.
+	File string `yaml:"file"`
+	// Directory is the root directory of the time-series of sets.
+	// If left empty, only File is read.
+	Directory string `yaml:"directory"`
+	// Times is a list of times which are read from the time-series.
+	// If left empty, all times are read.
+	Times []float64 `yaml:"times"`
+	// TimeName is the name of the time field.
+	// If left empty it is set to 'time'.
+	TimeName string `yaml:"time_name"`
+	// Set is the set name.
+	// If left empty, it is assumed to be the part of the file name
+	// preceding the first underscore.
+	Set string `yaml:"set"`
+	// Axis is the set axis type, i.e., one of 'distance', 'x', 'y', 'z',
+	// or 'xyz'. If it is 'xyz', the set has three coordinate fields,
+	// 'x', 'y' and 'z', otherwise it has a single field named after Axis.
+	Axis string `yaml:"axis"`
+	// Fields is a list of the names of the fields contained in the file.
+	// If left empty, the field names are derived from the file name,
+	// which fails for field names containing underscores, e.g., 'p_rgh'.
+	Fields []string `yaml:"fields"`
+	// Components maps field names to their number of components, e.g.,
+	// 3 for vectors. It is needed if vector or tensor values are written
+	// without parentheses, e.g., in the 'raw' format, and the file contains
+	// more than one field. Fields which are not listed are scalars.
+	Components map[string]int `yaml:"components"`
+
+	componentSpec `yaml:",inline"`
+}
+
+func defaultSets() *sets {
+	return &sets{
+		TimeName:      "time",
+		Axis:          "distance",
+		componentSpec: defaultComponentSpec(),
+	}
+}
+
+func NewSets(n *yaml.Node) (*sets, error) {
+	rw := defaultSets()
+	if err := n.Decode(rw); err != nil {
+		return nil, fmt.Errorf("sets: %w", err)
+	}
+	if rw.File == "" {
+		return nil, fmt.Errorf("sets: %w: %q", common.ErrUnsetField, "file")
+	}
+	for name, n := range rw.Components {
+		if n < 1 {
+			return nil, fmt.Errorf("sets: %w: %q: %q: %v",
+				common.ErrBadFieldValue, "components", name, n)
+		}
+	}
+	if err := rw.componentSpec.validate(); err != nil {
+		return nil, fmt.Errorf("sets: %w", err)
+	}
+	return rw, nil
+}
+
+// timeSeries returns a time-series reader which reads the set
+// from each time directory.
+func (rw *sets) timeSeries() (*timeSeries, error) {
+	spec := *rw
+	spec.Directory = ""
+	spec.Times = nil
+	ts := &timeSeries{
+		File:       rw.File,
+		Directory:  rw.Directory,
+		TimeName:   rw.TimeName,
+		Times:      rw.Times,
+		FormatSpec: Config{Type: "sets"},
+	}
+	if err := ts.FormatSpec.TypeSpec.Encode(&spec); err != nil {
+		return nil, fmt.Errorf("sets: %w", err)
+	}
+	return ts, nil
+}
+
+func (rw *sets) Read() (*dataframe.DataFrame, error) {
+	if rw.Directory != "" {
+		ts, err := rw.timeSeries()
+		if err != nil {
+			return nil, err
+		}
+		return ts.Read()
+	}
+	fn := func(name string) (io.ReadCloser, error) {
+		return os.Open(name)
+	}
+	return rw.ReadFromFn(fn)
+}
+
+func (rw *sets) ReadFromFn(fn ReaderFunc) (*dataframe.DataFrame, error) {
+	if rw.Directory != "" {
+		ts, err := rw.timeSeries()
+		if err != nil {
+			return nil, err
+		}
+		return ts.ReadFromFn(fn)
+	}
+	rc, err := fn(rw.File)
+	if err != nil {
+		return nil, fmt.Errorf("sets: %w", err)
+	}
+	defer rc.Close()
+	return rw.read(rc)
+}
+
+// fields returns the field names, either as defined by Fields,
+// or as encoded in the file name.
+func (rw *sets) fields() ([]string, error) {
+	if len(rw.Fields) > 0 {
+		return rw.Fields, nil
+	}
+	base := filepath.Base(rw.File)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	var rest string
+	if rw.Set != "" {
+		var found bool
+		if rest, found = strings.CutPrefix(base, rw.Set+"_"); !found {
+			return nil, fmt.Errorf("%w: %q", ErrSetsName, rw.File)
+		}
+	} else {
+		var found bool
+		if _, rest, found = strings.Cut(base, "_"); !found {
+			return nil, fmt.Errorf("%w: %q", ErrSetsName, rw.File)
+		}
+	}
+	if len(rest) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrSetsName, rw.File)
+	}
+	return strings.Split(rest, "_"), nil
+}
+
+func (rw *sets) read(in io.Reader) (*dataframe.DataFrame, error) {
+	fields, err := rw.fields()
+	if err != nil {
+		return nil, err
+	}
+	r := datenc.NewReader(in)
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("sets: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("sets: %w", io.ErrUnexpectedEOF)
+	}
+
+	header := []string{rw.Axis}
+	if strings.ToLower(rw.Axis) == "xyz" {
+		header = []string{"x", "y", "z"}
+	}
+	if len(r.Shapes()) <= len(header) {
+		return nil, fmt.Errorf("%w: no value columns", ErrSetsShape)
+	}
+	// the field shapes are known if fields are written as tuples,
+	// otherwise they must be defined by Components, unless the split of
+	// the value columns is unambiguous
+	shapes := r.Shapes()[len(header):]
+	nValues := len(records[0]) - len(header)
+	switch {
+	case len(rw.Components) > 0:
+		for f := range rw.Components {
+			if !slices.Contains(fields, f) {
+				return nil, fmt.Errorf("sets: %w: %q: %q",
+					common.ErrBadField, "components", f)
+			}
+		}
+		n := 0
+		shapes = make([]datenc.Shape, len(fields))
+		for i, f := range fields {
+			c := max(rw.Components[f], 1)
+			if c > 1 {
+				shapes[i] = make(datenc.Shape, c)
+			}
+			n += c
+		}
+		if n != nValues {
+			return nil, fmt.Errorf("%w: %v components in %v value columns",
+				ErrSetsShape, n, nValues)
+		}
+	case len(shapes) == len(fields):
+	case len(fields) == 1:
+		shapes = []datenc.Shape{make(datenc.Shape, nValues)}
+	default:
+		return nil, fmt.Errorf("%w: %v fields in %v value columns, set 'components'",
+			ErrSetsShape, len(fields), nValues)
+	}
+	for i, f := range fields {
+		header = rw.appendNames(header, f, nil, shapes[i])
+	}
+	records = append([][]string{header}, records...)
+
+	df := dataframe.LoadRecords(
+		records,
+		dataframe.HasHeader(true),
+		dataframe.DefaultType(series.Float),
+	)
+	if df.Error() != nil {
+		return nil, fmt.Errorf("sets: %w", df.Error())
+	}
+	return &df, nil
+}
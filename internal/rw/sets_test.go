@@ -0,0 +1,263 @@
+package rw
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type setsTest struct {
+	Name   string
+	Config string
+	Input  string
+	Output dataframe.DataFrame
+	Error  error
+}
+
+var setsReadTests = []setsTest{
+	{
+		Name: "good-scalars",
+		Config: `
+file: lineA_p_k.xy
+`,
+		Input: "0\t1\t2\n0.5\t3\t4\n",
+		Output: dataframe.New(
+			series.New([]float64{0, 0.5}, series.Float, "distance"),
+			series.New([]int{1, 3}, series.Int, "p"),
+			series.New([]int{2, 4}, series.Int, "k"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-vectors-xyz",
+		Config: `
+file: line_A_U_V.xy
+set: line_A
+axis: xyz
+components:
+  U: 3
+  V: 3
+`,
+		Input: "0 0 1\t1 2 3\t4 5 6\n",
+		Output: dataframe.New(
+			series.New([]int{0}, series.Int, "x"),
+			series.New([]int{0}, series.Int, "y"),
+			series.New([]int{1}, series.Int, "z"),
+			series.New([]int{1}, series.Int, "U_x"),
+			series.New([]int{2}, series.Int, "U_y"),
+			series.New([]int{3}, series.Int, "U_z"),
+			series.New([]int{4}, series.Int, "V_x"),
+			series.New([]int{5}, series.Int, "V_y"),
+			series.New([]int{6}, series.Int, "V_z"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-vector-single",
+		Config: `
+file: lineA_U.xy
+`,
+		Input: "0\t1\t2\t3\n",
+		Output: dataframe.New(
+			series.New([]int{0}, series.Int, "distance"),
+			series.New([]int{1}, series.Int, "U_x"),
+			series.New([]int{2}, series.Int, "U_y"),
+			series.New([]int{3}, series.Int, "U_z"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-fields",
+		Config: `
+file: lineA_p_rgh_U.xy
+fields: [p_rgh, U]
+`,
+		Input: "0\t1\t(2 3 4)\n",
+		Output: dataframe.New(
+			series.New([]int{0}, series.Int, "distance"),
+			series.New([]int{1}, series.Int, "p_rgh"),
+			series.New([]int{2}, series.Int, "U_x"),
+			series.New([]int{3}, series.Int, "U_y"),
+			series.New([]int{4}, series.Int, "U_z"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "bad-ambiguous",
+		Config: `
+file: lineA_p_U.xy
+`,
+		Input:  "0\t1\t2\t3\t4\n",
+		Output: dataframe.DataFrame{},
+		Error:  ErrSetsShape,
+	},
+	{
+		Name: "bad-components",
+		Config: `
+file: lineA_p_U.xy
+components:
+  U: 2
+`,
+		Input:  "0\t1\t2\t3\t4\n",
+		Output: dataframe.DataFrame{},
+		Error:  ErrSetsShape,
+	},
+	{
+		Name: "bad-components-field",
+		Config: `
+file: lineA_p_U.xy
+components:
+  V: 3
+`,
+		Input:  "0\t1\t2\t3\t4\n",
+		Output: dataframe.DataFrame{},
+		Error:  common.ErrBadField,
+	},
+	{
+		Name: "bad-name",
+		Config: `
+file: lineA.xy
+`,
+		Input:  "0\t1\n",
+		Output: dataframe.DataFrame{},
+		Error:  ErrSetsName,
+	},
+	{
+		Name: "bad-set-name",
+		Config: `
+file: lineA_p.xy
+set: lineB
+`,
+		Input:  "0\t1\n",
+		Output: dataframe.DataFrame{},
+		Error:  ErrSetsName,
+	},
+	{
+		Name: "bad-shape",
+		Config: `
+file: lineA_p_k.xy
+`,
+		Input:  "0\t1\t2\t3\n",
+		Output: dataframe.DataFrame{},
+		Error:  ErrSetsShape,
+	},
+}
+
+func TestSetsRead(t *testing.T) {
+	for _, tt := range setsReadTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			raw, err := io.ReadAll(strings.NewReader(tt.Config))
+			assert.Nil(err, "unexpected io.ReadAll() error")
+			var config yaml.Node
+			err = yaml.Unmarshal(raw, &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+
+			rw, err := NewSets(&config)
+			assert.Nil(err, "unexpected NewSets() error")
+			out, err := rw.read(strings.NewReader(tt.Input))
+
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error != nil {
+				assert.Nil(out)
+			} else {
+				assert.Equal(tt.Output, *out)
+			}
+		})
+	}
+}
+
+var setsSeriesReadTests = []setsTest{
+	{
+		Name: "good-series",
+		Config: `
+file: lineA_p_U.xy
+directory: testdata/foam_sets
+`,
+		Output: dataframe.New(
+			series.New([]float64{0.1, 0.1, 0.2, 0.2}, series.Float, "time"),
+			series.New([]float64{0, 0.5, 0, 0.5}, series.Float, "distance"),
+			series.New([]int{1, 2, 3, 4}, series.Int, "p"),
+			series.New([]int{0, 0, 0, 0}, series.Int, "U_x"),
+			series.New([]int{0, 0, 0, 0}, series.Int, "U_y"),
+			series.New([]int{1, 2, 3, 4}, series.Int, "U_z"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-series-raw",
+		Config: `
+file: lineA_p_U.xy
+directory: testdata/foam_sets_raw
+components:
+  U: 3
+`,
+		Output: dataframe.New(
+			series.New([]float64{0.1, 0.1}, series.Float, "time"),
+			series.New([]float64{0, 0.5}, series.Float, "distance"),
+			series.New([]int{1, 2}, series.Int, "p"),
+			series.New([]int{0, 0}, series.Int, "U_x"),
+			series.New([]int{0, 0}, series.Int, "U_y"),
+			series.New([]int{1, 2}, series.Int, "U_z"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "bad-series-raw",
+		Config: `
+file: lineA_p_U.xy
+directory: testdata/foam_sets_raw
+`,
+		Output: dataframe.DataFrame{},
+		Error:  ErrSetsShape,
+	},
+	{
+		Name: "good-series-times",
+		Config: `
+file: lineA_p_U.xy
+directory: testdata/foam_sets
+times: [0.2]
+`,
+		Output: dataframe.New(
+			series.New([]float64{0.2, 0.2}, series.Float, "time"),
+			series.New([]float64{0, 0.5}, series.Float, "distance"),
+			series.New([]int{3, 4}, series.Int, "p"),
+			series.New([]int{0, 0}, series.Int, "U_x"),
+			series.New([]int{0, 0}, series.Int, "U_y"),
+			series.New([]int{3, 4}, series.Int, "U_z"),
+		),
+		Error: nil,
+	},
+}
+
+func TestSetsSeriesRead(t *testing.T) {
+	for _, tt := range setsSeriesReadTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			raw, err := io.ReadAll(strings.NewReader(tt.Config))
+			assert.Nil(err, "unexpected io.ReadAll() error")
+			var config yaml.Node
+			err = yaml.Unmarshal(raw, &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+
+			rw, err := NewSets(&config)
+			assert.Nil(err, "unexpected NewSets() error")
+			out, err := rw.Read()
+
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error != nil {
+				assert.Nil(out)
+			} else {
+				assert.Equal(tt.Output, *out)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package rw
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrSplitPlaceholder = errors.New("split_by: type_spec does not reference field")
+	ErrSplitValue       = errors.New("split_by: bad field value")
+)
+
+// split partitions df into groups of rows holding the same values of
+// fields, in order of first appearance. It returns the groups and the
+// values of fields for each group.
+func split(df *dataframe.DataFrame, fields []string) ([]dataframe.DataFrame, [][]string, error) {
+	cols := make([]series.Series, len(fields))
+	for i, name := range fields {
+		if !slices.Contains(df.Names(), name) {
+			return nil, nil, fmt.Errorf("split_by: %w: %q", common.ErrBadField, name)
+		}
+		cols[i] = df.Col(name)
+	}
+	var values [][]string
+	var indices [][]int
+	groups := make(map[string]int)
+	for i := 0; i < df.Nrow(); i++ {
+		v := make([]string, len(cols))
+		for j := range cols {
+			v[j] = splitValue(cols[j].Elem(i))
+		}
+		key := strings.Join(v, "\x00")
+		g, found := groups[key]
+		if !found {
+			g = len(values)
+			groups[key] = g
+			values = append(values, v)
+			indices = append(indices, nil)
+		}
+		indices[g] = append(indices[g], i)
+	}
+	dfs := make([]dataframe.DataFrame, len(indices))
+	for g, idx := range indices {
+		dfs[g] = df.Subset(idx)
+		if dfs[g].Error() != nil {
+			return nil, nil, fmt.Errorf("split_by: %w", dfs[g].Error())
+		}
+	}
+	return dfs, values, nil
+}
+
+// splitValue returns the value of e as substituted into a type_spec,
+// where floating point values are written in the shortest exact form,
+// e.g., '0.5' instead of '0.500000'.
+func splitValue(e series.Element) string {
+	if e.Type() == series.Float && !e.IsNA() {
+		return strconv.FormatFloat(e.Float(), 'g', -1, 64)
+	}
+	return e.String()
+}
+
+// checkSplitValue checks whether the value v of field name can be
+// substituted into a type_spec, i.e., whether it is a valid file name
+// element, so that each output is written to a different file within
+// the configured directory.
+func checkSplitValue(name, v string) error {
+	if v == "" || v == "." || v == ".." || strings.ContainsAny(v, `/\`+"\x00") {
+		return fmt.Errorf("%w: %q: %q", ErrSplitValue, name, v)
+	}
+	return nil
+}
+
+// walkValues calls fn for each scalar value node of the tree rooted at n,
+// i.e., for each scalar node which is not a mapping key.
+func walkValues(n *yaml.Node, fn func(*yaml.Node)) {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		fn(n)
+	case yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			walkValues(n.Content[i], fn)
+		}
+	default:
+		for _, c := range n.Content {
+			walkValues(c, fn)
+		}
+	}
+}
+
+// substitute returns a copy of spec, with the placeholders in its values
+// replaced by r.
+func substitute(spec *yaml.Node, r *strings.Replacer) *yaml.Node {
+	c := copyNode(spec)
+	walkValues(c, func(n *yaml.Node) {
+		n.Value = r.Replace(n.Value)
+	})
+	return c
+}
+
+// copyNode returns a deep copy of the tree rooted at n.
+func copyNode(n *yaml.Node) *yaml.Node {
+	c := *n
+	c.Content = make([]*yaml.Node, len(n.Content))
+	for i := range n.Content {
+		c.Content[i] = copyNode(n.Content[i])
+	}
+	return &c
+}
+
+// writeSplit executes the Writer defined by config once for each group of
+// rows of df holding the same values of the config.SplitBy fields. Each
+// '{field}' placeholder in the config.TypeSpec values is replaced by
+// the value of the field for the group, e.g., 'probe_{probe}.csv'.
+// Mapping keys are left as is, and values which are not valid file name
+// elements, e.g., empty values or values containing path separators,
+// result in an error before any output is written.
+func writeSplit(df *dataframe.DataFrame, config *Config, factory WriterFactory) error {
+	for _, name := range config.SplitBy {
+		found := false
+		walkValues(&config.TypeSpec, func(n *yaml.Node) {
+			found = found || strings.Contains(n.Value, "{"+name+"}")
+		})
+		if !found {
+			return fmt.Errorf("%w: %q", ErrSplitPlaceholder, name)
+		}
+	}
+	dfs, values, err := split(df, config.SplitBy)
+	if err != nil {
+		return err
+	}
+	for g := range values {
+		for j, name := range config.SplitBy {
+			if err := checkSplitValue(name, values[g][j]); err != nil {
+				return err
+			}
+		}
+	}
+	for g := range dfs {
+		oldnew := make([]string, 0, 2*len(config.SplitBy))
+		for j, name := range config.SplitBy {
+			oldnew = append(oldnew, "{"+name+"}", values[g][j])
+		}
+		spec := substitute(&config.TypeSpec, strings.NewReplacer(oldnew...))
+		if common.Verbose {
+			log.Printf("output: writing group: %q", values[g])
+		}
+		if err := writeSpec(&dfs[g], config, factory, spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package rw
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+var splitInput dataframe.DataFrame = dataframe.New(
+	series.New([]string{"p0", "p1", "p0", "p1", "p0"}, series.String, "probe"),
+	series.New([]float64{0.5, 0.5, 1, 1, 1}, series.Float, "case"),
+	series.New([]int{1, 2, 3, 4, 5}, series.Int, "u"),
+)
+
+type splitTest struct {
+	Name   string
+	Config string
+	Input  []string // probe values of splitInput if set
+	Output map[string]dataframe.DataFrame
+	Error  error
+}
+
+var splitTests = []splitTest{
+	{
+		Name: "good-single",
+		Config: `
+type: ram
+split_by: [probe]
+type_spec:
+  name: probe_{probe}
+`,
+		Output: map[string]dataframe.DataFrame{
+			"probe_p0": splitInput.Subset([]int{0, 2, 4}),
+			"probe_p1": splitInput.Subset([]int{1, 3}),
+		},
+	},
+	{
+		Name: "good-multiple",
+		Config: `
+type: ram
+split_by: [probe, case]
+type_spec:
+  name: "{case}/{probe}"
+`,
+		Output: map[string]dataframe.DataFrame{
+			"0.5/p0": splitInput.Subset([]int{0}),
+			"0.5/p1": splitInput.Subset([]int{1}),
+			"1/p0":   splitInput.Subset([]int{2, 4}),
+			"1/p1":   splitInput.Subset([]int{3}),
+		},
+	},
+	{
+		Name: "good-max-rows",
+		Config: `
+type: ram
+split_by: [probe]
+max_rows: 2
+type_spec:
+  name: probe_{probe}
+`,
+		Output: map[string]dataframe.DataFrame{
+			"probe_p0": splitInput.Subset([]int{0, 4}),
+			"probe_p1": splitInput.Subset([]int{1, 3}),
+		},
+	},
+	{
+		Name: "bad-field",
+		Config: `
+type: ram
+split_by: [x]
+type_spec:
+  name: probe_{x}
+`,
+		Error: common.ErrBadField,
+	},
+	{
+		Name: "bad-placeholder",
+		Config: `
+type: ram
+split_by: [probe, case]
+type_spec:
+  name: probe_{probe}
+`,
+		Error: ErrSplitPlaceholder,
+	},
+	{
+		Name: "bad-value-separator",
+		Config: `
+type: ram
+split_by: [probe]
+type_spec:
+  name: probe_{probe}
+`,
+		Input: []string{"p0", "p/1", "p0", "p/1", "p0"},
+		Error: ErrSplitValue,
+	},
+	{
+		Name: "bad-value-backslash",
+		Config: `
+type: ram
+split_by: [probe]
+type_spec:
+  name: probe_{probe}
+`,
+		Input: []string{"p0", `p\1`, "p0", `p\1`, "p0"},
+		Error: ErrSplitValue,
+	},
+	{
+		Name: "bad-value-parent",
+		Config: `
+type: ram
+split_by: [probe]
+type_spec:
+  name: probe_{probe}
+`,
+		Input: []string{"p0", "..", "p0", "..", "p0"},
+		Error: ErrSplitValue,
+	},
+	{
+		Name: "bad-value-empty",
+		Config: `
+type: ram
+split_by: [probe]
+type_spec:
+  name: probe_{probe}
+`,
+		Input: []string{"p0", "", "p0", "", "p0"},
+		Error: ErrSplitValue,
+	},
+}
+
+func TestWriteSplit(t *testing.T) {
+	for _, tt := range splitTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			defer func() { RAM = nil }()
+
+			var config Config
+			err := yaml.Unmarshal([]byte(tt.Config), &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+
+			input := splitInput
+			if tt.Input != nil {
+				input = splitInput.Mutate(series.New(tt.Input, series.String, "probe"))
+			}
+			in := input.Copy()
+			err = write(&in, &config)
+			assert.ErrorIs(err, tt.Error)
+			assert.Equal(input, in)
+			if tt.Error != nil {
+				assert.Nil(RAM, "nothing should be written")
+				return
+			}
+			assert.Len(RAM.s, len(tt.Output))
+			for name, out := range tt.Output {
+				if assert.Contains(RAM.s, name) {
+					assert.Equal(out, *RAM.s[name])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteSplitFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	var config Config
+	err := yaml.Unmarshal([]byte(`
+type: csv
+split_by: [probe]
+type_spec:
+  file: `+filepath.Join(dir, "{probe}", "probe_{probe}.csv")+`
+`), &config)
+	assert.Nil(err, "unexpected yaml.Unmarshal() error")
+
+	in := splitInput.Copy()
+	assert.Nil(write(&in, &config))
+	for _, probe := range []string{"p0", "p1"} {
+		_, err := os.Stat(filepath.Join(dir, probe, "probe_"+probe+".csv"))
+		assert.Nil(err)
+	}
+	// the config is unchanged
+	assert.Contains(config.TypeSpec.Content[1].Value, "{probe}")
+}
+
+func TestSplitSubstitute(t *testing.T) {
+	assert := assert.New(t)
+
+	var spec yaml.Node
+	err := yaml.Unmarshal([]byte(`
+file: probe_{probe}.csv
+labels:
+  "{probe}": "{probe} [m]"
+fields: ["{probe}"]
+`), &spec)
+	assert.Nil(err, "unexpected yaml.Unmarshal() error")
+
+	out := substitute(spec.Content[0], strings.NewReplacer("{probe}", "p0"))
+	var config struct {
+		File   string            `yaml:"file"`
+		Labels map[string]string `yaml:"labels"`
+		Fields []string          `yaml:"fields"`
+	}
+	assert.Nil(out.Decode(&config))
+	assert.Equal("probe_p0.csv", config.File)
+	assert.Equal(map[string]string{"{probe}": "p0 [m]"}, config.Labels, "keys should be kept")
+	assert.Equal([]string{"p0"}, config.Fields)
+}
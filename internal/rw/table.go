@@ -0,0 +1,115 @@
+package rw
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+)
+
+// tableSpec defines the layout of a table written for documents,
+// e.g., a LaTeX or Markdown table.
+type tableSpec struct {
+	// File is the file path to which the table is written.
+	File string `yaml:"file"`
+	// EnforceExtension determines whether a file name extension will be
+	// enforced on the output file name.
+	EnforceExtension bool `yaml:"enforce_extension"`
+	// Labels maps field names to column header labels.
+	// Field names are used by default.
+	Labels map[string]string `yaml:"labels"`
+	// Align is the alignment of each column, one character per column,
+	// e.g., 'lrr'. By default, numeric columns are right-aligned and
+	// all other columns are left-aligned.
+	Align string `yaml:"align"`
+
+	numberSpec `yaml:",inline"`
+}
+
+func defaultTableSpec() tableSpec {
+	return tableSpec{
+		numberSpec: defaultNumberSpec(),
+	}
+}
+
+// validate checks whether the alignment characters are valid.
+func (t *tableSpec) validate(valid string) error {
+	for _, c := range t.Align {
+		if !strings.ContainsRune(valid, c) {
+			return fmt.Errorf("%w: %q: %q, expected one of %q",
+				common.ErrBadFieldValue, "align", t.Align, valid)
+		}
+	}
+	return t.numberSpec.validate()
+}
+
+// formatTableFloat is the default format of table floating point values.
+func formatTableFloat(f float64) string {
+	return fmt.Sprintf("%g", f)
+}
+
+// table returns the column header labels, the column alignment and
+// the formatted records of df. The alignment of numeric columns defaults
+// to numeric, and to 'l' for all other columns.
+func (t *tableSpec) table(df *dataframe.DataFrame, numeric byte) (labels []string, align []byte, records [][]string, err error) {
+	if df.Error() != nil {
+		return nil, nil, nil, df.Error()
+	}
+	if t.Align != "" && utf8.RuneCountInString(t.Align) != df.Ncol() {
+		return nil, nil, nil, fmt.Errorf("%w: %q: %q: got %v columns, expected %v",
+			common.ErrBadFieldValue, "align", t.Align,
+			utf8.RuneCountInString(t.Align), df.Ncol())
+	}
+	for name := range t.Labels {
+		if !slices.Contains(df.Names(), name) {
+			return nil, nil, nil, fmt.Errorf("%w: %q", common.ErrBadField, name)
+		}
+	}
+	records = t.numberSpec.formatRecords(df, formatTableFloat)
+	labels = records[0]
+	for i, name := range labels {
+		if l, found := t.Labels[name]; found {
+			labels[i] = l
+		}
+	}
+	align = []byte(t.Align)
+	if t.Align == "" {
+		for _, typ := range df.Types() {
+			if typ == series.Float || typ == series.Int {
+				align = append(align, numeric)
+			} else {
+				align = append(align, 'l')
+			}
+		}
+	}
+	return labels, align, records[1:], nil
+}
+
+// writeTable creates the output file of t and writes to it using fn.
+// The file name extension is set to ext if t.EnforceExtension is set.
+func (t *tableSpec) writeTable(ext string, fn func(io.Writer) error) error {
+	if t.File == "" {
+		return fmt.Errorf("%w: %v", common.ErrUnsetField, "file")
+	}
+	if err := OutDir(t.File); err != nil {
+		return err
+	}
+	path := t.File
+	if t.EnforceExtension {
+		path = SetExt(path, ext)
+	}
+	f, err := create(path)
+	if err != nil {
+		return err
+	}
+	if err := fn(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
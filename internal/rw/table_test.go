@@ -0,0 +1,243 @@
+package rw
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/Milover/post/internal/common"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type tableWriteTest struct {
+	Name   string
+	Config string
+	Input  *dataframe.DataFrame // tableWriteInput if nil
+	Output string
+	Error  error
+}
+
+var tableWriteInput dataframe.DataFrame = dataframe.New(
+	series.New([]float64{0.5, 2, math.NaN()}, series.Float, "time"),
+	series.New([]string{"a_b", "c|d", "e"}, series.String, "name"),
+	series.New([]int{1, -20, 300}, series.Int, "n"),
+)
+
+var markdownTableWriteTests = []tableWriteTest{
+	{
+		Name:   "good-default",
+		Config: ``,
+		Output: `| time | name |   n |
+| ---: | :--- | --: |
+|  0.5 | a_b  |   1 |
+|    2 | c\|d | -20 |
+|  NaN | e    | 300 |
+`,
+	},
+	{
+		Name: "good-labels-align",
+		Config: `
+labels:
+  time: $t$ [s]
+align: lcr
+`,
+		Output: `| $t$ [s] | name |   n |
+| :------ | :--: | --: |
+| 0.5     | a_b  |   1 |
+| 2       | c\|d | -20 |
+| NaN     |  e   | 300 |
+`,
+	},
+	{
+		Name: "good-float-format",
+		Config: `
+float_format: "%.2f"
+decimal_separator: ","
+nan: "-"
+`,
+		Output: `| time | name |   n |
+| ---: | :--- | --: |
+| 0,50 | a_b  |   1 |
+| 2,00 | c\|d | -20 |
+|    - | e    | 300 |
+`,
+	},
+	{
+		Name: "bad-align",
+		Config: `
+align: lr
+`,
+		Error: common.ErrBadFieldValue,
+	},
+	{
+		Name: "bad-label",
+		Config: `
+labels:
+  x: x
+`,
+		Error: common.ErrBadField,
+	},
+}
+
+func TestMarkdownTableWrite(t *testing.T) {
+	for _, tt := range markdownTableWriteTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var config yaml.Node
+			err := yaml.Unmarshal([]byte(tt.Config), &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+			rw, err := NewMarkdownTable(&config)
+			assert.Nil(err, "unexpected NewMarkdownTable() error")
+
+			var b bytes.Buffer
+			err = rw.write(&b, &tableWriteInput)
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error == nil {
+				assert.Equal(tt.Output, b.String())
+			}
+		})
+	}
+}
+
+var latexTableWriteTests = []tableWriteTest{
+	{
+		Name:   "good-default",
+		Config: ``,
+		Output: `\begin{tabular}{rlr}
+\toprule
+time & name & n \\
+\midrule
+0.5 & a\_b & 1 \\
+2 & c|d & -20 \\
+NaN & e & 300 \\
+\bottomrule
+\end{tabular}
+`,
+	},
+	{
+		Name: "good-siunitx",
+		Config: `
+siunitx: true
+labels:
+  time: $t$ [\si{\second}]
+float_format: "%.1e"
+`,
+		Output: `\begin{tabular}{SlS}
+\toprule
+{$t$ [\si{\second}]} & name & {n} \\
+\midrule
+5.0e-01 & a\_b & 1 \\
+2.0e+00 & c|d & -20 \\
+NaN & e & 300 \\
+\bottomrule
+\end{tabular}
+`,
+	},
+	{
+		Name: "good-align",
+		Config: `
+align: crl
+float_format: "%f"
+precision:
+  time: 3
+`,
+		Output: `\begin{tabular}{crl}
+\toprule
+time & name & n \\
+\midrule
+0.500 & a\_b & 1 \\
+2.000 & c|d & -20 \\
+NaN & e & 300 \\
+\bottomrule
+\end{tabular}
+`,
+	},
+	{
+		Name: "good-escape",
+		Config: `
+siunitx: true
+labels:
+  p_rgh: $p_{rgh}$
+nan: '%'
+inf: '$\infty$'
+`,
+		Input: func() *dataframe.DataFrame {
+			df := dataframe.New(
+				series.New([]float64{1, math.NaN(), math.Inf(1), math.Inf(-1)}, series.Float, "U_x"),
+				series.New([]float64{0, 1, 2, 3}, series.Float, "p_rgh"),
+				series.New([]string{"a", "b", "c", "d"}, series.String, "patch_name"),
+			)
+			return &df
+		}(),
+		Output: `\begin{tabular}{SSl}
+\toprule
+{U\_x} & {$p_{rgh}$} & patch\_name \\
+\midrule
+1 & 0 & a \\
+\% & 1 & b \\
+\$\textbackslash{}infty\$ & 2 & c \\
+-\$\textbackslash{}infty\$ & 3 & d \\
+\bottomrule
+\end{tabular}
+`,
+	},
+	{
+		Name: "bad-align",
+		Config: `
+align: lrrr
+`,
+		Error: common.ErrBadFieldValue,
+	},
+}
+
+func TestLaTeXTableWrite(t *testing.T) {
+	for _, tt := range latexTableWriteTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var config yaml.Node
+			err := yaml.Unmarshal([]byte(tt.Config), &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+			rw, err := NewLaTeXTable(&config)
+			assert.Nil(err, "unexpected NewLaTeXTable() error")
+
+			in := tt.Input
+			if in == nil {
+				in = &tableWriteInput
+			}
+			var b bytes.Buffer
+			err = rw.write(&b, in)
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error == nil {
+				assert.Equal(tt.Output, b.String())
+			}
+		})
+	}
+}
+
+func TestNewTable(t *testing.T) {
+	for _, tt := range []struct {
+		Name   string
+		Type   string
+		Config string
+		Error  error
+	}{
+		{"good-latex", "latex-table", "align: lcrS", nil},
+		{"bad-latex", "latex-table", "align: lp", common.ErrBadFieldValue},
+		{"good-markdown", "markdown-table", "align: lcr", nil},
+		{"bad-markdown", "markdown-table", "align: lS", common.ErrBadFieldValue},
+		{"bad-precision", "markdown-table", "precision: {x: -1}", common.ErrBadFieldValue},
+	} {
+		t.Run(tt.Name, func(t *testing.T) {
+			var config yaml.Node
+			err := yaml.Unmarshal([]byte(tt.Config), &config)
+			assert.Nil(t, err, "unexpected yaml.Unmarshal() error")
+			_, err = Writers[tt.Type](&config)
+			assert.ErrorIs(t, err, tt.Error)
+		})
+	}
+}
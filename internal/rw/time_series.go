@@ -1,19 +1,29 @@
 package rw
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"log"
 	"os"
+	"path"
+	"slices"
 	"strconv"
+	"sync"
 
+	"github.com/Milover/post/internal/archived"
 	"github.com/Milover/post/internal/common"
 	"github.com/go-gota/gota/dataframe"
 	"github.com/go-gota/gota/series"
 	"gopkg.in/yaml.v3"
 )
 
+var (
+	ErrTimeSeriesShape = errors.New("time-series: data files differ in shape")
+)
+
 // timeSeries contains data needed for parsing a table time series,
 // which is of the following format:
 //
@@ -40,6 +50,29 @@ type timeSeries struct {
 	// TimeName is the name of the time field.
 	// If left empty it is set to 'time'.
 	TimeName string `yaml:"time_name"`
+	// Times is a list of times (directories) which are read.
+	// If left empty, all times are read.
+	Times []float64 `yaml:"times"`
+	// SkipNonNumeric determines whether directories whose names are not
+	// numbers, e.g., 'constant' or '0.orig', are skipped. If it is not set,
+	// such directories result in an error.
+	SkipNonNumeric bool `yaml:"skip_non_numeric"`
+	// StartTime is the earliest time which is read, if defined.
+	StartTime *float64 `yaml:"start_time"`
+	// EndTime is the latest time which is read, if defined.
+	EndTime *float64 `yaml:"end_time"`
+	// Stride determines that every Stride-th time is read, starting from
+	// the earliest selected time. If left empty it is set to 1.
+	Stride int `yaml:"stride"`
+	// Latest determines that only the latest Latest times are read,
+	// if it is greater than 0.
+	Latest int `yaml:"latest"`
+	// Earliest determines that only the earliest Earliest times are read,
+	// if it is greater than 0.
+	Earliest int `yaml:"earliest"`
+	// Workers is the number of files which are read concurrently.
+	// If left empty, the global number of workers is used.
+	Workers int `yaml:"workers"`
 	// FormatSpec is the config for the series file type input,
 	// e.g., if the series consists of CSV files, FormatSpec would define
 	// a config for a CSV input type.
@@ -49,6 +82,7 @@ type timeSeries struct {
 func defaultTimeSeries() *timeSeries {
 	return &timeSeries{
 		TimeName: "time",
+		Stride:   1,
 	}
 }
 
@@ -63,16 +97,35 @@ func NewTimeSeries(n *yaml.Node) (*timeSeries, error) {
 	if rw.Directory == "" {
 		return nil, fmt.Errorf("time-series: %w: %q", common.ErrUnsetField, "directory")
 	}
+	if rw.Stride < 1 {
+		return nil, fmt.Errorf("time-series: %w: %q: %v", common.ErrBadFieldValue, "stride", rw.Stride)
+	}
+	if rw.Workers < 0 {
+		return nil, fmt.Errorf("time-series: %w: %q: %v", common.ErrBadFieldValue, "workers", rw.Workers)
+	}
+	if rw.Latest < 0 {
+		return nil, fmt.Errorf("time-series: %w: %q: %v", common.ErrBadFieldValue, "latest", rw.Latest)
+	}
+	if rw.Earliest < 0 {
+		return nil, fmt.Errorf("time-series: %w: %q: %v", common.ErrBadFieldValue, "earliest", rw.Earliest)
+	}
+	if rw.Latest > 0 && rw.Earliest > 0 {
+		return nil, fmt.Errorf("time-series: %w: %q and %q are mutually exclusive",
+			common.ErrBadFieldValue, "latest", "earliest")
+	}
+	if rw.StartTime != nil && rw.EndTime != nil && *rw.StartTime > *rw.EndTime {
+		return nil, fmt.Errorf("time-series: %w: %q > %q: %v > %v",
+			common.ErrBadFieldValue, "start_time", "end_time", *rw.StartTime, *rw.EndTime)
+	}
 	return rw, nil
 }
 
-// walkStruct is a helper struct for holding data related to walking a
-// foam series directory structure.
-type walkStruct struct {
-	// Time is the time (directory) currently being processed.
+// timeDir is a time directory of a time-series.
+type timeDir struct {
+	// Name is the directory name.
+	Name string
+	// Time is the time parsed from the directory name.
 	Time float64
-	// Rows is a slice used for building the time field.
-	Rows []float64
 }
 
 func (rw *timeSeries) Read() (*dataframe.DataFrame, error) {
@@ -95,76 +148,230 @@ func (rw *timeSeries) ReadFromFn(fn ReaderFunc) (*dataframe.DataFrame, error) {
 	return rw.read(fsys)
 }
 
-func (rw *timeSeries) read(fsys fs.FS) (*dataframe.DataFrame, error) {
-	var df *dataframe.DataFrame
-	var ws walkStruct
-	// FIXME: the dataframe.DataFrame operations are mysterious, so no idea
-	// where allocations happen or how many there are --- should check this
-	// at some point.
-	// OPTIMIZE: we should skip directories (return fs.SkipDir) which are not
-	// on the correct path to the time-series root directory.
-	walkFn := func(path string, d fs.DirEntry, err error) error {
-		// stop walking on any error, since there shouldn't be any
+// readTimeDirs returns the time directories of fsys, sorted by time.
+// Only the root directory of fsys is read, hence nested directories
+// are never visited. Directories whose names are not numbers result in
+// an error, unless skipNonNumeric is set, in which case they are skipped.
+func readTimeDirs(fsys fs.FS, skipNonNumeric bool) ([]timeDir, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	dirs := make([]timeDir, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		t, err := strconv.ParseFloat(e.Name(), 64)
 		if err != nil {
-			return err
+			if skipNonNumeric {
+				continue
+			}
+			return nil, err
 		}
-		// regular FSs need to skip the root directory ("."),
-		// archiveFSs also need to skip until the time-series root directory
-		if path == "." || path == rw.Directory {
-			return nil
+		dirs = append(dirs, timeDir{Name: e.Name(), Time: t})
+	}
+	slices.SortStableFunc(dirs, func(a, b timeDir) int {
+		return cmp.Compare(a.Time, b.Time)
+	})
+	return dirs, nil
+}
+
+// times returns the selected time directories of fsys, sorted by time.
+func (rw *timeSeries) times(fsys fs.FS) ([]timeDir, error) {
+	all, err := readTimeDirs(fsys, rw.SkipNonNumeric)
+	if err != nil {
+		return nil, err
+	}
+	dirs := all[:0]
+	for _, d := range all {
+		if len(rw.Times) > 0 && !slices.Contains(rw.Times, d.Time) {
+			continue
 		}
-		// the directory name is the current time
-		if d.IsDir() {
-			ws.Time, err = strconv.ParseFloat(d.Name(), 64)
-			return err
+		if (rw.StartTime != nil && d.Time < *rw.StartTime) ||
+			(rw.EndTime != nil && d.Time > *rw.EndTime) {
+			continue
 		}
-		// only process the specified files
-		if d.Name() != rw.File {
-			return nil
+		dirs = append(dirs, d)
+	}
+	if rw.Stride > 1 {
+		n := 0
+		for i := 0; i < len(dirs); i += rw.Stride {
+			dirs[n] = dirs[i]
+			n++
 		}
+		dirs = dirs[:n]
+	}
+	if rw.Latest > 0 && rw.Latest < len(dirs) {
+		dirs = dirs[len(dirs)-rw.Latest:]
+	}
+	if rw.Earliest > 0 && rw.Earliest < len(dirs) {
+		dirs = dirs[:rw.Earliest]
+	}
+	return dirs, nil
+}
 
-		fn := func(_ string) (io.ReadCloser, error) {
-			return fsys.Open(path)
-		}
-		temp, err := ReadFromFn(fn, &rw.FormatSpec)
+// readFile reads the data file of the time directory dir.
+// It returns nil if the data file does not exist.
+func (rw *timeSeries) readFile(fsys fs.FS, dir timeDir) (*dataframe.DataFrame, error) {
+	file := path.Join(dir.Name, rw.File)
+	// time directories without the data file are skipped
+	if _, err := fs.Stat(fsys, file); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	fn := func(_ string) (io.ReadCloser, error) {
+		return fsys.Open(file)
+	}
+	df, err := ReadFromFn(fn, &rw.FormatSpec)
+	if err != nil {
+		return nil, fmt.Errorf("%w: in file: %v", err, file)
+	}
+	return df, nil
+}
+
+// readFiles reads the data files of all time directories dirs, using
+// a pool of workers, and returns the data in the order of dirs.
+// The first error, in the order of dirs, is returned.
+func (rw *timeSeries) readFiles(fsys fs.FS, dirs []timeDir) ([]*dataframe.DataFrame, error) {
+	dfs := make([]*dataframe.DataFrame, len(dirs))
+	errs := make([]error, len(dirs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(rw.workers(), len(dirs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				dfs[i], errs[i] = rw.readFile(fsys, dirs[i])
+			}
+		}()
+	}
+	for i := range dirs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	for _, err := range errs {
 		if err != nil {
-			return fmt.Errorf("%w: in file: %v", err, path)
-		}
-		// all files should have the same number of rows, so we allocate
-		// only once, hence we can error if this is not the case
-		if len(ws.Rows) == 0 {
-			ws.Rows = make([]float64, temp.Nrow())
+			return nil, err
 		}
-		for i := range ws.Rows {
-			ws.Rows[i] = ws.Time
+	}
+	return dfs, nil
+}
+
+// workers returns the number of workers used for reading files.
+func (rw *timeSeries) workers() int {
+	if rw.Workers > 0 {
+		return rw.Workers
+	}
+	return max(common.Workers, 1)
+}
+
+// sameFields reports whether a and b hold the same field names,
+// regardless of order.
+func sameFields(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, name := range a {
+		if !slices.Contains(b, name) {
+			return false
 		}
-		*temp = dataframe.New(series.New(
-			ws.Rows, series.Float, rw.TimeName)).CBind(*temp)
-		if temp.Error() != nil {
-			return fmt.Errorf("%w: in file: %v", temp.Error(), path)
+	}
+	return true
+}
+
+// rbind concatenates the rows of dfs, which must all have the same fields,
+// in a single pass. The field order and types are those of
+// the first dataframe.
+func rbind(dfs []*dataframe.DataFrame) (dataframe.DataFrame, error) {
+	if len(dfs) == 0 {
+		return dataframe.DataFrame{}, nil
+	}
+	names := dfs[0].Names()
+	types := dfs[0].Types()
+	nRows := 0
+	for _, df := range dfs {
+		if !sameFields(names, df.Names()) {
+			return dataframe.DataFrame{}, fmt.Errorf(
+				"rbind: fields differ: %q != %q", df.Names(), names)
 		}
-		// concatonate the new dataframe
-		if df == nil {
-			df = temp
-			return nil
+		nRows += df.Nrow()
+	}
+	ss := make([]series.Series, len(names))
+	for j, name := range names {
+		if types[j] == series.Float {
+			vals := make([]float64, 0, nRows)
+			for _, df := range dfs {
+				vals = append(vals, df.Col(name).Float()...)
+			}
+			ss[j] = series.New(vals, series.Float, name)
+			continue
 		}
-		*df = df.RBind(*temp)
-		if df.Error() != nil {
-			return fmt.Errorf("%w: in file: %v", df.Error(), path)
+		vals := make([]string, 0, nRows)
+		for _, df := range dfs {
+			vals = append(vals, df.Col(name).Records()...)
 		}
-		return nil
+		ss[j] = series.New(vals, types[j], name)
 	}
-	if err := fs.WalkDir(fsys, ".", walkFn); err != nil {
+	df := dataframe.New(ss...)
+	return df, df.Error()
+}
+
+func (rw *timeSeries) read(fsys fs.FS) (*dataframe.DataFrame, error) {
+	dirs, err := rw.times(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("time-series: %w", err)
+	}
+	files := make([]string, len(dirs))
+	for i, d := range dirs {
+		files[i] = path.Join(d.Name, rw.File)
+	}
+	archived.Prefetch(fsys, files)
+	all, err := rw.readFiles(fsys, dirs)
+	if err != nil {
 		return nil, fmt.Errorf("time-series: %w", err)
 	}
-	if df != nil {
-		if common.Verbose {
-			log.Printf("time-series: sorting by time")
+	// all files should have the same number of rows
+	var dfs []*dataframe.DataFrame
+	var times []float64
+	for i, temp := range all {
+		if temp == nil {
+			continue
+		}
+		if len(dfs) > 0 && temp.Nrow() != dfs[0].Nrow() {
+			return nil, fmt.Errorf("%w: rows: %v != %v: in file: %v",
+				ErrTimeSeriesShape, temp.Nrow(), dfs[0].Nrow(), path.Join(dirs[i].Name, rw.File))
 		}
-		*df = df.Arrange(dataframe.Sort(rw.TimeName))
-		if df.Error() != nil {
-			return nil, fmt.Errorf("time-series: %w", df.Error())
+		if len(dfs) > 0 && !sameFields(temp.Names(), dfs[0].Names()) {
+			return nil, fmt.Errorf("%w: fields: %q != %q: in file: %v",
+				ErrTimeSeriesShape, temp.Names(), dfs[0].Names(), path.Join(dirs[i].Name, rw.File))
+		}
+		dfs = append(dfs, temp)
+		for j := 0; j < temp.Nrow(); j++ {
+			times = append(times, dirs[i].Time)
 		}
 	}
-	return df, nil
+	if len(dfs) == 0 {
+		return nil, nil
+	}
+	if common.Verbose {
+		log.Printf("time-series: concatenating %v files", len(dfs))
+	}
+	df, err := rbind(dfs)
+	if err != nil {
+		return nil, fmt.Errorf("time-series: %w", err)
+	}
+	df = dataframe.New(series.New(times, series.Float, rw.TimeName)).CBind(df)
+	if df.Error() != nil {
+		return nil, fmt.Errorf("time-series: %w", df.Error())
+	}
+	if common.Verbose {
+		log.Printf("time-series: sorting by time")
+	}
+	df = df.Arrange(dataframe.Sort(rw.TimeName))
+	if df.Error() != nil {
+		return nil, fmt.Errorf("time-series: %w", df.Error())
+	}
+	return &df, nil
 }
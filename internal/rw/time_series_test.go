@@ -89,6 +89,64 @@ format_spec:
 directory: 'testdata/foam_series.good_unsorted'
 file: data.dat
 time_name: 'time'
+format_spec:
+  type: dat
+`,
+		Output: dataframe.New(
+			series.New([]float64{
+				0, 0,
+				1, 1,
+				2, 2,
+				3, 3,
+				4, 4,
+				5, 5,
+				6, 6,
+				7, 7,
+				8, 8,
+				9, 9,
+				10, 10,
+				11, 11,
+				12, 12,
+				13, 13,
+				14, 14,
+				15, 15,
+				16, 16,
+				17, 17,
+				18, 18,
+				19, 19,
+				20, 20}, series.Float, "time"),
+			series.Ints([]int{
+				0, 0,
+				0, 1,
+				0, 2,
+				0, 3,
+				0, 4,
+				0, 5,
+				0, 6,
+				0, 7,
+				0, 8,
+				0, 9,
+				0, 10,
+				0, 11,
+				0, 12,
+				0, 13,
+				0, 14,
+				0, 15,
+				0, 16,
+				0, 17,
+				0, 18,
+				0, 19,
+				0, 20}),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-unsorted-workers",
+		Config: `
+directory: 'testdata/foam_series.good_unsorted'
+file: data.dat
+workers: 4
+time_name: 'time'
 format_spec:
   type: dat
 `,
@@ -166,6 +224,80 @@ format_spec:
 		SkipCompare: true,
 		Error:       nil,
 	},
+	{
+		Name: "good-range-stride",
+		Config: `
+directory: 'testdata/foam_series.good_unsorted'
+file: data.dat
+start_time: 5
+end_time: 15
+stride: 5
+format_spec:
+  type: dat
+`,
+		Output: dataframe.New(
+			series.New([]float64{5, 5, 10, 10, 15, 15}, series.Float, "time"),
+			series.Ints([]int{0, 5, 0, 10, 0, 15}),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-latest",
+		Config: `
+directory: 'testdata/foam_series.good_unsorted'
+file: data.dat
+latest: 2
+format_spec:
+  type: dat
+`,
+		Output: dataframe.New(
+			series.New([]float64{19, 19, 20, 20}, series.Float, "time"),
+			series.Ints([]int{0, 19, 0, 20}),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-earliest-times",
+		Config: `
+directory: 'testdata/foam_series.good_unsorted'
+file: data.dat
+times: [3, 4, 12]
+earliest: 1
+format_spec:
+  type: dat
+`,
+		Output: dataframe.New(
+			series.New([]float64{3, 3}, series.Float, "time"),
+			series.Ints([]int{0, 3}),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-skip-non-numeric",
+		Config: `
+directory: 'testdata/foam_series.good_non_numeric'
+file: data.dat
+skip_non_numeric: true
+format_spec:
+  type: dat
+`,
+		Output: dataframe.New(
+			series.New([]float64{1, 2}, series.Float, "time"),
+			series.Ints([]int{1, 2}),
+		),
+		Error: nil,
+	},
+	{
+		Name: "bad-non-numeric",
+		Config: `
+directory: 'testdata/foam_series.good_non_numeric'
+file: data.dat
+format_spec:
+  type: dat
+`,
+		Output: dataframe.DataFrame{},
+		Error:  errors.New("error"), // not matching explicitly, so doesn't matter
+	},
 	{
 		Name: "bad-unequal-rows",
 		Config: `
@@ -0,0 +1,131 @@
+package rw
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Milover/post/internal/common"
+	datenc "github.com/Milover/post/internal/encoding/dat"
+	vtkenc "github.com/Milover/post/internal/encoding/vtk"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrVtkSize = errors.New("vtk: array and point/cell counts differ")
+)
+
+// vtk contains data needed for parsing VTK files, either in the legacy
+// ASCII format, e.g., '.vtk', or the XML PolyData or UnstructuredGrid
+// formats, i.e., '.vtp' and '.vtu', with ASCII or inline binary data.
+// The format is detected from the file contents.
+//
+// The output contains the 'x', 'y' and 'z' coordinate fields, followed by
+// the data arrays. Components of multi-component arrays become separate
+// fields, named by their index by default, e.g., 'U_0', 'U_1', 'U_2'.
+type vtk struct {
+	// File is the file path of the VTK file.
+	File string `yaml:"file"`
+	// Data selects which data is read, either 'point' or 'cell'.
+	// If 'cell' is selected, the coordinates are those of the cell centers.
+	// If left empty it is set to 'point'.
+	Data string `yaml:"data"`
+
+	componentSpec `yaml:",inline"`
+}
+
+func defaultVtk() *vtk {
+	rw := &vtk{
+		Data:          "point",
+		componentSpec: defaultComponentSpec(),
+	}
+	rw.ComponentSuffix = SuffixIndex
+	return rw
+}
+
+func NewVtk(n *yaml.Node) (*vtk, error) {
+	rw := defaultVtk()
+	if err := n.Decode(rw); err != nil {
+		return nil, fmt.Errorf("vtk: %w", err)
+	}
+	rw.Data = strings.ToLower(rw.Data)
+	if rw.Data != "point" && rw.Data != "cell" {
+		return nil, fmt.Errorf("vtk: %w: %q: %q", common.ErrBadFieldValue, "data", rw.Data)
+	}
+	if err := rw.componentSpec.validate(); err != nil {
+		return nil, fmt.Errorf("vtk: %w", err)
+	}
+	return rw, nil
+}
+
+func (rw *vtk) Read() (*dataframe.DataFrame, error) {
+	fn := func(name string) (io.ReadCloser, error) {
+		return os.Open(name)
+	}
+	return rw.ReadFromFn(fn)
+}
+
+func (rw *vtk) ReadFromFn(fn ReaderFunc) (*dataframe.DataFrame, error) {
+	rc, err := fn(rw.File)
+	if err != nil {
+		return nil, fmt.Errorf("vtk: %w", err)
+	}
+	defer rc.Close()
+	return rw.read(rc)
+}
+
+func (rw *vtk) read(in io.Reader) (*dataframe.DataFrame, error) {
+	d, err := vtkenc.Read(in)
+	if err != nil {
+		return nil, err
+	}
+	coords, arrays := d.Points, d.PointData
+	if rw.Data == "cell" {
+		coords, arrays = d.CellCenters(), d.CellData
+	}
+	n := len(coords) / 3
+	// cell centers are unavailable if the dataset defines no cells,
+	// so only the cell data is output
+	if rw.Data == "cell" && len(d.Cells) == 0 {
+		coords = nil
+		if len(arrays) > 0 {
+			n = arrays[0].NTuples()
+		}
+	}
+
+	ss := make([]series.Series, 0, 3+len(arrays))
+	if coords != nil || len(arrays) == 0 {
+		for i, name := range []string{"x", "y", "z"} {
+			vals := make([]float64, n)
+			for j := range vals {
+				vals[j] = coords[3*j+i]
+			}
+			ss = append(ss, series.New(vals, series.Float, name))
+		}
+	}
+	for _, a := range arrays {
+		if a.NTuples() != n || len(a.Values) != n*a.NComp {
+			return nil, fmt.Errorf("%w: %q: %v != %v", ErrVtkSize, a.Name, a.NTuples(), n)
+		}
+		var shape datenc.Shape
+		if a.NComp > 1 {
+			shape = make(datenc.Shape, a.NComp)
+		}
+		for j, name := range rw.names(a.Name, nil, shape) {
+			vals := make([]float64, n)
+			for k := range vals {
+				vals[k] = a.Values[k*a.NComp+j]
+			}
+			ss = append(ss, series.New(vals, series.Float, name))
+		}
+	}
+	df := dataframe.New(ss...)
+	if df.Error() != nil {
+		return nil, fmt.Errorf("vtk: %w", df.Error())
+	}
+	return &df, nil
+}
@@ -0,0 +1,150 @@
+package rw
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	vtkenc "github.com/Milover/post/internal/encoding/vtk"
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type vtkTest struct {
+	Name   string
+	Config string
+	Input  string
+	Output dataframe.DataFrame
+	Error  error
+}
+
+const vtkLegacy string = `# vtk DataFile Version 3.0
+two triangles
+ASCII
+DATASET POLYDATA
+POINTS 4 float
+0 0 0 1 0 0 1 1 0 0 1 0
+POLYGONS 2 8
+3 0 1 2
+3 0 2 3
+POINT_DATA 4
+SCALARS p float
+LOOKUP_TABLE default
+1 2 3 4
+VECTORS U float
+1 0 0 2 0 0 3 0 0 4 0 0
+CELL_DATA 2
+SCALARS id int
+LOOKUP_TABLE default
+0 1
+`
+
+const vtkXML string = `<?xml version="1.0"?>
+<VTKFile type="PolyData" version="1.0" byte_order="LittleEndian">
+  <PolyData>
+    <Piece NumberOfPoints="2" NumberOfVerts="2">
+      <PointData>
+        <DataArray type="Float64" Name="p" format="ascii">1 2</DataArray>
+      </PointData>
+      <Points>
+        <DataArray type="Float64" NumberOfComponents="3" format="ascii">0 0 0 1 0 0</DataArray>
+      </Points>
+    </Piece>
+  </PolyData>
+</VTKFile>
+`
+
+var vtkReadTests = []vtkTest{
+	{
+		Name:   "good-legacy-point",
+		Config: ``,
+		Input:  vtkLegacy,
+		Output: dataframe.New(
+			series.New([]float64{0, 1, 1, 0}, series.Float, "x"),
+			series.New([]float64{0, 0, 1, 1}, series.Float, "y"),
+			series.New([]float64{0, 0, 0, 0}, series.Float, "z"),
+			series.New([]float64{1, 2, 3, 4}, series.Float, "p"),
+			series.New([]float64{1, 2, 3, 4}, series.Float, "U_0"),
+			series.New([]float64{0, 0, 0, 0}, series.Float, "U_1"),
+			series.New([]float64{0, 0, 0, 0}, series.Float, "U_2"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-legacy-point-xyz",
+		Config: `
+component_suffix: xyz
+`,
+		Input: vtkLegacy,
+		Output: dataframe.New(
+			series.New([]float64{0, 1, 1, 0}, series.Float, "x"),
+			series.New([]float64{0, 0, 1, 1}, series.Float, "y"),
+			series.New([]float64{0, 0, 0, 0}, series.Float, "z"),
+			series.New([]float64{1, 2, 3, 4}, series.Float, "p"),
+			series.New([]float64{1, 2, 3, 4}, series.Float, "U_x"),
+			series.New([]float64{0, 0, 0, 0}, series.Float, "U_y"),
+			series.New([]float64{0, 0, 0, 0}, series.Float, "U_z"),
+		),
+		Error: nil,
+	},
+	{
+		Name: "good-legacy-cell",
+		Config: `
+data: cell
+`,
+		Input: vtkLegacy,
+		Output: dataframe.New(
+			series.New([]float64{2.0 / 3.0, 1.0 / 3.0}, series.Float, "x"),
+			series.New([]float64{1.0 / 3.0, 2.0 / 3.0}, series.Float, "y"),
+			series.New([]float64{0, 0}, series.Float, "z"),
+			series.New([]float64{0, 1}, series.Float, "id"),
+		),
+		Error: nil,
+	},
+	{
+		Name:   "good-xml",
+		Config: ``,
+		Input:  vtkXML,
+		Output: dataframe.New(
+			series.New([]float64{0, 1}, series.Float, "x"),
+			series.New([]float64{0, 0}, series.Float, "y"),
+			series.New([]float64{0, 0}, series.Float, "z"),
+			series.New([]float64{1, 2}, series.Float, "p"),
+		),
+		Error: nil,
+	},
+	{
+		Name:   "bad-format",
+		Config: ``,
+		Input:  "0 1 2\n",
+		Output: dataframe.DataFrame{},
+		Error:  vtkenc.ErrFormat,
+	},
+}
+
+func TestVtkRead(t *testing.T) {
+	for _, tt := range vtkReadTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			raw, err := io.ReadAll(strings.NewReader(tt.Config))
+			assert.Nil(err, "unexpected io.ReadAll() error")
+			var config yaml.Node
+			err = yaml.Unmarshal(raw, &config)
+			assert.Nil(err, "unexpected yaml.Unmarshal() error")
+
+			rw, err := NewVtk(&config)
+			assert.Nil(err, "unexpected NewVtk() error")
+			out, err := rw.read(strings.NewReader(tt.Input))
+
+			assert.ErrorIs(err, tt.Error)
+			if tt.Error != nil {
+				assert.Nil(out)
+			} else {
+				assert.Equal(tt.Output, *out)
+			}
+		})
+	}
+}